@@ -0,0 +1,200 @@
+package ark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/eino/components/embedding"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+const defaultBaseURL = "https://ark.cn-beijing.volces.com/api/v3"
+
+// IsMultimodalModel doubao-embedding-vision 系列需要使用多模态向量接口
+func IsMultimodalModel(model string) bool {
+	return strings.Contains(strings.ToLower(model), "embedding-vision")
+}
+
+type EmbeddingConfig struct {
+	APIKey string
+	// 模型名称或推理接入点ID(ep-xxx)
+	Model string
+	// 默认 https://ark.cn-beijing.volces.com/api/v3, 以 # 结尾时原样作为请求地址
+	BaseURL    string
+	HTTPClient *http.Client
+	// 输出向量维度, doubao-embedding-vision-250615 支持 1024/2048
+	Dimension *int
+}
+
+// Embedder 方舟多模态向量接口, 每次请求的所有输入融合为一个向量
+type Embedder struct {
+	cfg        *EmbeddingConfig
+	httpClient *http.Client
+	endpoint   string
+}
+
+func NewEmbedder(ctx context.Context, cfg *EmbeddingConfig) (embedding.Embedder, error) {
+	if cfg == nil || cfg.Model == "" || cfg.APIKey == "" {
+		return nil, errors.New("invalid embedding config")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Embedder{
+		cfg:        cfg,
+		httpClient: httpClient,
+		endpoint:   normalizeEndpoint(cfg.BaseURL),
+	}, nil
+}
+
+func normalizeEndpoint(u string) string {
+	if strings.HasSuffix(u, "#") {
+		return strings.TrimSuffix(u, "#")
+	}
+	if u == "" {
+		u = defaultBaseURL
+	}
+	u = strings.TrimSuffix(u, "/")
+	u = strings.TrimSuffix(u, "/embeddings/multimodal")
+	u = strings.TrimSuffix(u, "/embeddings")
+	return u + "/embeddings/multimodal"
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+type inputPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type apiRequest struct {
+	Model          string      `json:"model"`
+	Input          []inputPart `json:"input"`
+	EncodingFormat string      `json:"encoding_format"`
+	Dimensions     *int        `json:"dimensions,omitempty"`
+}
+
+type apiEmbedding struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+type apiResponse struct {
+	// 多模态接口返回单个对象, 兼容返回数组的网关
+	Data  json.RawMessage `json:"data"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	resp, err := e.EmbedStringsExt(ctx, texts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]float64, 0, len(resp.Embeddings))
+	for _, item := range resp.Embeddings {
+		out = append(out, item.Embedding)
+	}
+	return out, nil
+}
+
+func (e *Embedder) EmbedStringsExt(ctx context.Context, texts []string, opts ...embedding.Option) (*domain.EmbeddingsResponse, error) {
+	inputs := make([]domain.EmbeddingInput, 0, len(texts))
+	for _, text := range texts {
+		inputs = append(inputs, domain.EmbeddingInput{Text: text})
+	}
+	return e.EmbedInputs(ctx, inputs, opts...)
+}
+
+// EmbedInputs 每条输入单独请求, 输入中的文本与图片融合为一个向量
+func (e *Embedder) EmbedInputs(ctx context.Context, inputs []domain.EmbeddingInput, opts ...embedding.Option) (*domain.EmbeddingsResponse, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("inputs is empty")
+	}
+	out := &domain.EmbeddingsResponse{
+		Embeddings: make([]domain.EmbeddingItem, 0, len(inputs)),
+	}
+	for i, input := range inputs {
+		parts := make([]inputPart, 0, 2)
+		if input.Text != "" {
+			parts = append(parts, inputPart{Type: "text", Text: input.Text})
+		}
+		if input.ImageURL != "" {
+			parts = append(parts, inputPart{Type: "image_url", ImageURL: &imageURL{URL: input.ImageURL}})
+		}
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("input %d is empty", i)
+		}
+		vec, tokens, err := e.embed(ctx, parts)
+		if err != nil {
+			return nil, err
+		}
+		out.Embeddings = append(out.Embeddings, domain.EmbeddingItem{Embedding: vec, TextIndex: i})
+		out.Usage.TotalTokens += tokens
+	}
+	return out, nil
+}
+
+func (e *Embedder) embed(ctx context.Context, parts []inputPart) ([]float64, int, error) {
+	raw, err := json.Marshal(apiRequest{
+		Model:          e.cfg.Model,
+		Input:          parts,
+		EncodingFormat: "float",
+		Dimensions:     e.cfg.Dimension,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return nil, 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var ar apiResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&ar)
+	if resp.StatusCode != http.StatusOK {
+		if ar.Error != nil && ar.Error.Message != "" {
+			return nil, 0, fmt.Errorf("%s: %s", resp.Status, ar.Error.Message)
+		}
+		return nil, 0, errors.New(resp.Status)
+	}
+	if decodeErr != nil {
+		return nil, 0, decodeErr
+	}
+
+	var single apiEmbedding
+	if err := json.Unmarshal(ar.Data, &single); err != nil {
+		var list []apiEmbedding
+		if err := json.Unmarshal(ar.Data, &list); err != nil || len(list) == 0 {
+			return nil, 0, errors.New("empty embeddings")
+		}
+		single = list[0]
+	}
+	if len(single.Embedding) == 0 {
+		return nil, 0, errors.New("empty embeddings")
+	}
+	return single.Embedding, ar.Usage.TotalTokens, nil
+}
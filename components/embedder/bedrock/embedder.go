@@ -0,0 +1,206 @@
+package bedrock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/components/embedding"
+
+	bedrockModel "github.com/chaitin/ModelKit/v2/components/model/bedrock"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/pkg/sigv4"
+)
+
+// cohere 单次请求最多 96 条文本
+const cohereMaxBatch = 96
+
+type EmbeddingConfig struct {
+	Model string
+	// bedrock-runtime 地址, 例如 https://bedrock-runtime.us-east-1.amazonaws.com
+	BaseURL string
+	// 需要已配置 SigV4 签名(或 Bedrock API Key)的客户端
+	HTTPClient *http.Client
+	// 向量维度, titan-embed-text-v2 支持 256/512/1024
+	Dimension *int
+	// 文本类型 query/document, 映射为 cohere 的 search_query/search_document
+	TextType *string
+}
+
+type Embedder struct {
+	cfg        *EmbeddingConfig
+	httpClient *http.Client
+	baseURL    string
+}
+
+func NewEmbedder(ctx context.Context, cfg *EmbeddingConfig) (embedding.Embedder, error) {
+	if cfg == nil || cfg.Model == "" || cfg.BaseURL == "" {
+		return nil, errors.New("invalid embedding config")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Embedder{
+		cfg:        cfg,
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(strings.TrimSuffix(cfg.BaseURL, "#"), "/"),
+	}, nil
+}
+
+type titanRequest struct {
+	InputText  string `json:"inputText"`
+	Dimensions *int   `json:"dimensions,omitempty"`
+	Normalize  *bool  `json:"normalize,omitempty"`
+}
+
+type titanResponse struct {
+	Embedding           []float64 `json:"embedding"`
+	InputTextTokenCount int       `json:"inputTextTokenCount"`
+}
+
+type cohereRequest struct {
+	Texts           []string `json:"texts"`
+	InputType       string   `json:"input_type"`
+	Truncate        string   `json:"truncate,omitempty"`
+	OutputDimension *int     `json:"output_dimension,omitempty"`
+}
+
+type cohereResponse struct {
+	// v3 返回二维数组, 指定 embedding_types 或 v4 时返回 {"float": [...]}
+	Embeddings json.RawMessage `json:"embeddings"`
+}
+
+func (e *Embedder) isCohere() bool {
+	return strings.Contains(e.cfg.Model, "cohere.embed")
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	resp, err := e.EmbedStringsExt(ctx, texts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]float64, 0, len(resp.Embeddings))
+	for _, item := range resp.Embeddings {
+		out = append(out, item.Embedding)
+	}
+	return out, nil
+}
+
+func (e *Embedder) EmbedStringsExt(ctx context.Context, texts []string, opts ...embedding.Option) (*domain.EmbeddingsResponse, error) {
+	if len(texts) == 0 {
+		return nil, errors.New("texts is empty")
+	}
+	out := &domain.EmbeddingsResponse{
+		Embeddings: make([]domain.EmbeddingItem, 0, len(texts)),
+	}
+	if e.isCohere() {
+		for start := 0; start < len(texts); start += cohereMaxBatch {
+			end := min(start+cohereMaxBatch, len(texts))
+			if err := e.embedCohere(ctx, texts[start:end], start, out); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	// titan 每次请求只接受一条文本
+	for i, text := range texts {
+		if err := e.embedTitan(ctx, text, i, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (e *Embedder) embedTitan(ctx context.Context, text string, index int, out *domain.EmbeddingsResponse) error {
+	body := titanRequest{InputText: text}
+	// titan v1 不支持 dimensions/normalize 参数
+	if !strings.Contains(e.cfg.Model, "titan-embed-text-v1") {
+		normalize := true
+		body.Dimensions = e.cfg.Dimension
+		body.Normalize = &normalize
+	}
+	var tr titanResponse
+	if _, err := e.invoke(ctx, body, &tr); err != nil {
+		return err
+	}
+	if len(tr.Embedding) == 0 {
+		return errors.New("empty embeddings")
+	}
+	out.Embeddings = append(out.Embeddings, domain.EmbeddingItem{Embedding: tr.Embedding, TextIndex: index})
+	out.Usage.TotalTokens += tr.InputTextTokenCount
+	return nil
+}
+
+func (e *Embedder) embedCohere(ctx context.Context, texts []string, offset int, out *domain.EmbeddingsResponse) error {
+	inputType := "search_document"
+	if e.cfg.TextType != nil && strings.ToLower(*e.cfg.TextType) == "query" {
+		inputType = "search_query"
+	}
+	body := cohereRequest{
+		Texts:     texts,
+		InputType: inputType,
+		Truncate:  "END",
+	}
+	if strings.Contains(e.cfg.Model, "embed-v4") {
+		body.OutputDimension = e.cfg.Dimension
+	}
+	var cr cohereResponse
+	tokens, err := e.invoke(ctx, body, &cr)
+	if err != nil {
+		return err
+	}
+
+	var dense [][]float64
+	if err := json.Unmarshal(cr.Embeddings, &dense); err != nil {
+		var typed struct {
+			Float [][]float64 `json:"float"`
+		}
+		if err := json.Unmarshal(cr.Embeddings, &typed); err != nil {
+			return err
+		}
+		dense = typed.Float
+	}
+	if len(dense) != len(texts) {
+		return errors.New("embeddings count mismatch")
+	}
+	for i, vec := range dense {
+		out.Embeddings = append(out.Embeddings, domain.EmbeddingItem{Embedding: vec, TextIndex: offset + i})
+	}
+	out.Usage.TotalTokens += tokens
+	return nil
+}
+
+// invoke 调用 InvokeModel, 返回响应头中的输入 token 数
+func (e *Embedder) invoke(ctx context.Context, body any, out any) (int, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
+	}
+	endpoint := e.baseURL + "/model/" + sigv4.EscapePathSegment(e.cfg.Model) + "/invoke"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, bedrockModel.ParseError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, err
+	}
+	tokens, _ := strconv.Atoi(resp.Header.Get("X-Amzn-Bedrock-Input-Token-Count"))
+	return tokens, nil
+}
@@ -0,0 +1,51 @@
+package bedrock
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+	"github.com/chaitin/ModelKit/v2/pkg/sigv4"
+)
+
+var testCredentials = sigv4.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+func TestEmbedder_Titan(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"POST /model/amazon.titan-embed-text-v2:0/invoke": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.EscapedPath() != "/model/amazon.titan-embed-text-v2%3A0/invoke" {
+				t.Errorf("model id not escaped: %s", r.URL.EscapedPath())
+			}
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"dimensions":256`) {
+				t.Errorf("dimensions not passed: %s", body)
+			}
+			standin.JSON(`{"embedding":[0.1,0.2,0.3],"inputTextTokenCount":4}`)(w, r)
+		},
+	}, standin.SigV4(testCredentials, "us-west-2", "bedrock"))
+
+	signer, err := sigv4.NewSigner(testCredentials, "us-west-2", "bedrock")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	dim := 256
+	emb, err := NewEmbedder(context.Background(), &EmbeddingConfig{
+		Model:      "amazon.titan-embed-text-v2:0",
+		BaseURL:    ts.URL,
+		HTTPClient: &http.Client{Transport: sigv4.NewTransport(signer, http.DefaultTransport)},
+		Dimension:  &dim,
+	})
+	if err != nil {
+		t.Fatalf("NewEmbedder failed: %v", err)
+	}
+	resp, err := emb.(*Embedder).EmbedStringsExt(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("EmbedStringsExt failed: %v", err)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[1].TextIndex != 1 || resp.Usage.TotalTokens != 8 {
+		t.Fatalf("unexpected embeddings: %+v", resp)
+	}
+}
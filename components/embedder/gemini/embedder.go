@@ -0,0 +1,262 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cloudwego/eino/components/embedding"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+const (
+	defaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+	// batchEmbedContents 单次请求最多 100 条文本
+	maxBatch = 100
+)
+
+type EmbeddingConfig struct {
+	APIKey string
+	// 模型名称, 例如 gemini-embedding-001, text-embedding-004, 可带 models/ 前缀
+	Model string
+	// 默认 https://generativelanguage.googleapis.com/v1beta, 以 # 结尾时原样使用
+	BaseURL    string
+	HTTPClient *http.Client
+	// 输出向量维度, 映射为 outputDimensionality
+	Dimension *int
+	// 文本类型 query/document, 映射为 RETRIEVAL_QUERY/RETRIEVAL_DOCUMENT, 其余值原样作为 taskType
+	TextType *string
+	// 响应未返回 usageMetadata 时额外调用 countTokens 统计用量, 每批多一次请求, 默认不统计
+	CountTokens *bool
+}
+
+type Embedder struct {
+	cfg        *EmbeddingConfig
+	httpClient *http.Client
+	baseURL    string
+	model      string
+}
+
+func NewEmbedder(ctx context.Context, cfg *EmbeddingConfig) (embedding.Embedder, error) {
+	if cfg == nil || cfg.Model == "" || cfg.APIKey == "" {
+		return nil, errors.New("invalid embedding config")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Embedder{
+		cfg:        cfg,
+		httpClient: httpClient,
+		baseURL:    normalizeBaseURL(cfg.BaseURL),
+		model:      strings.TrimPrefix(cfg.Model, "models/"),
+	}, nil
+}
+
+// normalizeBaseURL 未指定版本时补全 /v1beta
+func normalizeBaseURL(u string) string {
+	if u == "" {
+		return defaultBaseURL
+	}
+	if strings.HasSuffix(u, "#") {
+		return strings.TrimSuffix(strings.TrimSuffix(u, "#"), "/")
+	}
+	u = strings.TrimSuffix(u, "/")
+	if parsed, err := url.Parse(u); err == nil && (parsed.Path == "" || parsed.Path == "/") {
+		return u + "/v1beta"
+	}
+	return u
+}
+
+type part struct {
+	Text string `json:"text"`
+}
+
+type content struct {
+	Parts []part `json:"parts"`
+}
+
+type embedRequest struct {
+	Model                string  `json:"model"`
+	Content              content `json:"content"`
+	TaskType             string  `json:"taskType,omitempty"`
+	OutputDimensionality *int    `json:"outputDimensionality,omitempty"`
+}
+
+type batchEmbedRequest struct {
+	Requests []embedRequest `json:"requests"`
+}
+
+type contentEmbedding struct {
+	Values []float64 `json:"values"`
+}
+
+type usageMetadata struct {
+	PromptTokenCount int `json:"promptTokenCount"`
+	TotalTokenCount  int `json:"totalTokenCount"`
+}
+
+type embedResponse struct {
+	Embedding     *contentEmbedding  `json:"embedding,omitempty"`
+	Embeddings    []contentEmbedding `json:"embeddings,omitempty"`
+	UsageMetadata *usageMetadata     `json:"usageMetadata,omitempty"`
+}
+
+type countTokensRequest struct {
+	Contents []content `json:"contents"`
+}
+
+type countTokensResponse struct {
+	TotalTokens int `json:"totalTokens"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+func (e *Embedder) taskType() string {
+	if e.cfg.TextType == nil || *e.cfg.TextType == "" {
+		return "RETRIEVAL_DOCUMENT"
+	}
+	switch strings.ToLower(*e.cfg.TextType) {
+	case "query":
+		return "RETRIEVAL_QUERY"
+	case "document":
+		return "RETRIEVAL_DOCUMENT"
+	default:
+		return strings.ToUpper(*e.cfg.TextType)
+	}
+}
+
+func (e *Embedder) newRequest(text string) embedRequest {
+	req := embedRequest{
+		Model:    "models/" + e.model,
+		Content:  content{Parts: []part{{Text: text}}},
+		TaskType: e.taskType(),
+	}
+	if e.cfg.Dimension != nil && *e.cfg.Dimension > 0 {
+		req.OutputDimensionality = e.cfg.Dimension
+	}
+	return req
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	resp, err := e.EmbedStringsExt(ctx, texts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]float64, 0, len(resp.Embeddings))
+	for _, item := range resp.Embeddings {
+		out = append(out, item.Embedding)
+	}
+	return out, nil
+}
+
+func (e *Embedder) EmbedStringsExt(ctx context.Context, texts []string, opts ...embedding.Option) (*domain.EmbeddingsResponse, error) {
+	if len(texts) == 0 {
+		return nil, errors.New("texts is empty")
+	}
+	out := &domain.EmbeddingsResponse{
+		Embeddings: make([]domain.EmbeddingItem, 0, len(texts)),
+	}
+	for start := 0; start < len(texts); start += maxBatch {
+		end := min(start+maxBatch, len(texts))
+		if err := e.embedBatch(ctx, texts[start:end], start, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// embedBatch 单条文本使用 embedContent, 多条使用 batchEmbedContents
+func (e *Embedder) embedBatch(ctx context.Context, texts []string, offset int, out *domain.EmbeddingsResponse) error {
+	var (
+		er  embedResponse
+		err error
+	)
+	if len(texts) == 1 {
+		err = e.post(ctx, "embedContent", e.newRequest(texts[0]), &er)
+		if er.Embedding != nil {
+			er.Embeddings = []contentEmbedding{*er.Embedding}
+		}
+	} else {
+		body := batchEmbedRequest{Requests: make([]embedRequest, 0, len(texts))}
+		for _, text := range texts {
+			body.Requests = append(body.Requests, e.newRequest(text))
+		}
+		err = e.post(ctx, "batchEmbedContents", body, &er)
+	}
+	if err != nil {
+		return err
+	}
+	if len(er.Embeddings) != len(texts) {
+		return errors.New("embeddings count mismatch")
+	}
+	for i, emb := range er.Embeddings {
+		out.Embeddings = append(out.Embeddings, domain.EmbeddingItem{Embedding: emb.Values, TextIndex: offset + i})
+	}
+	out.Usage.TotalTokens += e.usage(ctx, texts, er.UsageMetadata)
+	return nil
+}
+
+// usage 优先使用响应中的 usageMetadata, 未返回时仅在开启 CountTokens 后通过 countTokens 统计,
+// 统计失败不影响向量结果
+func (e *Embedder) usage(ctx context.Context, texts []string, um *usageMetadata) int {
+	if um != nil {
+		if um.TotalTokenCount > 0 {
+			return um.TotalTokenCount
+		}
+		return um.PromptTokenCount
+	}
+	if e.cfg.CountTokens == nil || !*e.cfg.CountTokens {
+		return 0
+	}
+	body := countTokensRequest{Contents: make([]content, 0, len(texts))}
+	for _, text := range texts {
+		body.Contents = append(body.Contents, content{Parts: []part{{Text: text}}})
+	}
+	var cr countTokensResponse
+	if err := e.post(ctx, "countTokens", body, &cr); err != nil {
+		return 0
+	}
+	return cr.TotalTokens
+}
+
+func (e *Embedder) post(ctx context.Context, method string, body any, out any) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/models/%s:%s", e.baseURL, url.PathEscape(e.model), method)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", e.cfg.APIKey)
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil && errResp.Error.Message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, errResp.Error.Message)
+		}
+		return errors.New(resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,55 @@
+package gemini
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+func TestEmbedder_Usage(t *testing.T) {
+	cases := []struct {
+		name        string
+		response    string
+		countTokens bool
+		want        int
+		wantCounts  int32
+	}{
+		{"usageMetadata", `{"embeddings":[{"values":[0.1]},{"values":[0.2]}],"usageMetadata":{"promptTokenCount":5}}`, true, 5, 0},
+		{"missing usage", `{"embeddings":[{"values":[0.1]},{"values":[0.2]}]}`, false, 0, 0},
+		{"countTokens", `{"embeddings":[{"values":[0.1]},{"values":[0.2]}]}`, true, 7, 1},
+	}
+	for _, tc := range cases {
+		var counts atomic.Int32
+		ts := standin.New(t, standin.Routes{
+			"POST /v1beta/models/gemini-embedding-001:batchEmbedContents": standin.JSON(tc.response),
+			"POST /v1beta/models/gemini-embedding-001:countTokens": func(w http.ResponseWriter, r *http.Request) {
+				counts.Add(1)
+				standin.JSON(`{"totalTokens":7}`)(w, r)
+			},
+		}, standin.Header("x-goog-api-key", "gemini-key"))
+
+		emb, err := NewEmbedder(context.Background(), &EmbeddingConfig{
+			APIKey:      "gemini-key",
+			Model:       "gemini-embedding-001",
+			BaseURL:     ts.URL,
+			CountTokens: &tc.countTokens,
+		})
+		if err != nil {
+			t.Fatalf("NewEmbedder failed: %v", err)
+		}
+		resp, err := emb.(*Embedder).EmbedStringsExt(context.Background(), []string{"a", "b"})
+		if err != nil {
+			t.Fatalf("%s: EmbedStringsExt failed: %v", tc.name, err)
+		}
+		if len(resp.Embeddings) != 2 || resp.Usage.TotalTokens != tc.want {
+			t.Errorf("%s: unexpected response: %+v", tc.name, resp)
+		}
+		// 未开启或已返回 usageMetadata 时不应额外请求 countTokens
+		if got := counts.Load(); got != tc.wantCounts {
+			t.Errorf("%s: expected %d countTokens calls, got %d", tc.name, tc.wantCounts, got)
+		}
+	}
+}
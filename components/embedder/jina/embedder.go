@@ -0,0 +1,206 @@
+package jina
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/eino/components/embedding"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+const defaultBaseURL = "https://api.jina.ai/v1"
+
+type EmbeddingConfig struct {
+	APIKey string
+	Model  string
+	// 默认 https://api.jina.ai/v1, 以 # 结尾时原样作为请求地址
+	BaseURL    string
+	HTTPClient *http.Client
+	// 输出向量维度(Matryoshka 截断)
+	Dimension *int
+	// 文本类型 query/document, 未设置 Task 时映射为 retrieval.query/retrieval.passage
+	TextType *string
+	// 任务类型, 例如 retrieval.query、retrieval.passage、text-matching、code.query
+	Task *string
+	// 迟分块, 对全部输入整体编码后再分块池化
+	LateChunking *bool
+	// 多向量输出, jina-colbert 系列始终为多向量
+	MultiVector *bool
+}
+
+type Embedder struct {
+	cfg        *EmbeddingConfig
+	httpClient *http.Client
+	endpoint   string
+}
+
+func NewEmbedder(ctx context.Context, cfg *EmbeddingConfig) (embedding.Embedder, error) {
+	if cfg == nil || cfg.Model == "" || cfg.APIKey == "" {
+		return nil, errors.New("invalid embedding config")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Embedder{
+		cfg:        cfg,
+		httpClient: httpClient,
+		endpoint:   normalizeEndpoint(cfg.BaseURL, isColbert(cfg.Model)),
+	}, nil
+}
+
+func isColbert(model string) bool {
+	return strings.Contains(strings.ToLower(model), "colbert")
+}
+
+// normalizeEndpoint colbert 模型使用 /multi-vector, 其余使用 /embeddings
+func normalizeEndpoint(u string, colbert bool) string {
+	if strings.HasSuffix(u, "#") {
+		return strings.TrimSuffix(u, "#")
+	}
+	if u == "" {
+		u = defaultBaseURL
+	}
+	u = strings.TrimSuffix(u, "/")
+	u = strings.TrimSuffix(u, "/embeddings")
+	u = strings.TrimSuffix(u, "/multi-vector")
+	if colbert {
+		return u + "/multi-vector"
+	}
+	return u + "/embeddings"
+}
+
+type apiRequest struct {
+	Model             string   `json:"model"`
+	Input             []string `json:"input"`
+	EmbeddingType     string   `json:"embedding_type,omitempty"`
+	Task              string   `json:"task,omitempty"`
+	InputType         string   `json:"input_type,omitempty"`
+	Dimensions        *int     `json:"dimensions,omitempty"`
+	LateChunking      *bool    `json:"late_chunking,omitempty"`
+	ReturnMultivector *bool    `json:"return_multivector,omitempty"`
+}
+
+type apiResponse struct {
+	Data []struct {
+		Index      int         `json:"index"`
+		Embedding  []float64   `json:"embedding,omitempty"`
+		Embeddings [][]float64 `json:"embeddings,omitempty"`
+	} `json:"data"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func (e *Embedder) isQuery() bool {
+	return e.cfg.TextType != nil && strings.ToLower(*e.cfg.TextType) == "query"
+}
+
+func (e *Embedder) buildRequest(texts []string) apiRequest {
+	body := apiRequest{
+		Model:         e.cfg.Model,
+		Input:         texts,
+		EmbeddingType: "float",
+		Dimensions:    e.cfg.Dimension,
+	}
+	if isColbert(e.cfg.Model) {
+		// colbert 使用 input_type 区分查询与文档, 不支持 task/late_chunking
+		body.InputType = "document"
+		if e.isQuery() {
+			body.InputType = "query"
+		}
+		return body
+	}
+	switch {
+	case e.cfg.Task != nil && *e.cfg.Task != "":
+		body.Task = *e.cfg.Task
+	case e.cfg.TextType != nil && *e.cfg.TextType != "":
+		body.Task = "retrieval.passage"
+		if e.isQuery() {
+			body.Task = "retrieval.query"
+		}
+	}
+	body.LateChunking = e.cfg.LateChunking
+	if e.cfg.MultiVector != nil && *e.cfg.MultiVector {
+		body.ReturnMultivector = e.cfg.MultiVector
+	}
+	return body
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	resp, err := e.EmbedStringsExt(ctx, texts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]float64, 0, len(resp.Embeddings))
+	for _, item := range resp.Embeddings {
+		if item.Embedding == nil && len(item.MultiEmbedding) > 0 {
+			return nil, errors.New("multi-vector embeddings are only available via EmbedStringsExt")
+		}
+		out = append(out, item.Embedding)
+	}
+	return out, nil
+}
+
+func (e *Embedder) EmbedStringsExt(ctx context.Context, texts []string, opts ...embedding.Option) (*domain.EmbeddingsResponse, error) {
+	if len(texts) == 0 {
+		return nil, errors.New("texts is empty")
+	}
+	// late_chunking 依赖整批输入的上下文, 因此不拆分批次
+	raw, err := json.Marshal(e.buildRequest(texts))
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var ar apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ar); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New(resp.Status)
+		}
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		if ar.Detail != "" {
+			return nil, fmt.Errorf("%s: %s", resp.Status, ar.Detail)
+		}
+		return nil, errors.New(resp.Status)
+	}
+	if len(ar.Data) != len(texts) {
+		return nil, errors.New("embeddings count mismatch")
+	}
+
+	out := &domain.EmbeddingsResponse{
+		Embeddings: make([]domain.EmbeddingItem, 0, len(ar.Data)),
+		Usage:      domain.EmbeddingUsage{TotalTokens: ar.Usage.TotalTokens},
+	}
+	for _, d := range ar.Data {
+		item := domain.EmbeddingItem{TextIndex: d.Index}
+		if len(d.Embeddings) > 0 {
+			item.MultiEmbedding = d.Embeddings
+		} else {
+			item.Embedding = d.Embedding
+		}
+		out.Embeddings = append(out.Embeddings, item)
+	}
+	return out, nil
+}
@@ -0,0 +1,242 @@
+package tei
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/components/embedding"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+// 未能读取 /info 时使用的默认单次请求条数, 与 TEI 的 --max-client-batch-size 默认值一致
+const defaultMaxBatch = 32
+
+type EmbeddingConfig struct {
+	// TEI 服务地址, 例如 http://localhost:8080
+	BaseURL string
+	// 启动 TEI 时设置了 --api-key 才需要
+	APIKey     string
+	HTTPClient *http.Client
+	// 输出维度, 仅支持 Matryoshka 的模型生效
+	Dimension *int
+	// 输出类型 dense、sparse、dense&sparse, 默认 dense
+	OutputType *string
+}
+
+type Embedder struct {
+	cfg        *EmbeddingConfig
+	httpClient *http.Client
+	baseURL    string
+
+	info InfoCache
+}
+
+func NewEmbedder(ctx context.Context, cfg *EmbeddingConfig) (embedding.Embedder, error) {
+	if cfg == nil || cfg.BaseURL == "" {
+		return nil, errors.New("invalid embedding config")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Embedder{
+		cfg:        cfg,
+		httpClient: httpClient,
+		baseURL:    NormalizeBaseURL(cfg.BaseURL),
+	}, nil
+}
+
+// NormalizeBaseURL 去掉结尾的 #、/v1 以及具体接口路径, 得到 TEI 服务根地址
+func NormalizeBaseURL(u string) string {
+	u = strings.TrimSuffix(strings.TrimSuffix(u, "#"), "/")
+	for _, suffix := range []string{"/embed_sparse", "/embed", "/rerank", "/info", "/v1/embeddings", "/v1"} {
+		if s, ok := strings.CutSuffix(u, suffix); ok {
+			return s
+		}
+	}
+	return u
+}
+
+// FetchInfo 读取 /info 中的模型与批量限制
+func FetchInfo(ctx context.Context, httpClient *http.Client, baseURL, apiKey string) (*domain.TEIInfo, error) {
+	var info domain.TEIInfo
+	if _, err := Do(ctx, httpClient, http.MethodGet, baseURL+"/info", apiKey, nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// InfoCache 缓存 /info 的成功结果, 失败时下次调用重新读取, 每次读取使用调用方的 ctx
+type InfoCache struct {
+	mu   sync.Mutex
+	info *domain.TEIInfo
+}
+
+func (c *InfoCache) Get(ctx context.Context, httpClient *http.Client, baseURL, apiKey string) (*domain.TEIInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.info != nil {
+		return c.info, nil
+	}
+	info, err := FetchInfo(ctx, httpClient, baseURL, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	c.info = info
+	return info, nil
+}
+
+// Do 发送请求并解析 JSON 响应, 返回响应头中的 x-compute-tokens
+func Do(ctx context.Context, httpClient *http.Client, method, endpoint, apiKey string, body any, out any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error     string `json:"error"`
+			ErrorType string `json:"error_type"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil && errResp.Error != "" {
+			return 0, fmt.Errorf("%s: %s", resp.Status, errResp.Error)
+		}
+		return 0, errors.New(resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, err
+	}
+	tokens, _ := strconv.Atoi(resp.Header.Get("X-Compute-Tokens"))
+	return tokens, nil
+}
+
+// Info 读取并缓存 /info, 失败时调用方按默认限制继续
+func (e *Embedder) Info(ctx context.Context) (*domain.TEIInfo, error) {
+	return e.info.Get(ctx, e.httpClient, e.baseURL, e.cfg.APIKey)
+}
+
+func (e *Embedder) maxBatch(ctx context.Context) int {
+	if info, err := e.Info(ctx); err == nil && info.MaxClientBatchSize > 0 {
+		return info.MaxClientBatchSize
+	}
+	return defaultMaxBatch
+}
+
+type embedRequest struct {
+	Inputs []string `json:"inputs"`
+	// 超过 /info 中 max_input_length 的输入由服务端截断而不是报错
+	Truncate   bool `json:"truncate"`
+	Dimensions *int `json:"dimensions,omitempty"`
+}
+
+type sparseValue struct {
+	Index int     `json:"index"`
+	Value float64 `json:"value"`
+}
+
+func (e *Embedder) outputType() (dense, sparse bool) {
+	if e.cfg.OutputType == nil || *e.cfg.OutputType == "" {
+		return true, false
+	}
+	ot := strings.ToLower(*e.cfg.OutputType)
+	return strings.Contains(ot, "dense"), strings.Contains(ot, "sparse")
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	resp, err := e.EmbedStringsExt(ctx, texts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]float64, 0, len(resp.Embeddings))
+	for _, item := range resp.Embeddings {
+		out = append(out, item.Embedding)
+	}
+	return out, nil
+}
+
+func (e *Embedder) EmbedStringsExt(ctx context.Context, texts []string, opts ...embedding.Option) (*domain.EmbeddingsResponse, error) {
+	if len(texts) == 0 {
+		return nil, errors.New("texts is empty")
+	}
+	dense, sparse := e.outputType()
+	if !dense && !sparse {
+		return nil, fmt.Errorf("invalid output_type: %s; allowed: dense, sparse, dense&sparse", *e.cfg.OutputType)
+	}
+
+	out := &domain.EmbeddingsResponse{
+		Embeddings: make([]domain.EmbeddingItem, len(texts)),
+	}
+	for i := range out.Embeddings {
+		out.Embeddings[i].TextIndex = i
+	}
+	batch := e.maxBatch(ctx)
+	for start := 0; start < len(texts); start += batch {
+		end := min(start+batch, len(texts))
+		body := embedRequest{Inputs: texts[start:end], Truncate: true}
+		if dense {
+			var vectors [][]float64
+			body.Dimensions = e.cfg.Dimension
+			tokens, err := Do(ctx, e.httpClient, http.MethodPost, e.baseURL+"/embed", e.cfg.APIKey, body, &vectors)
+			if err != nil {
+				return nil, err
+			}
+			if len(vectors) != end-start {
+				return nil, errors.New("embeddings count mismatch")
+			}
+			for i, vec := range vectors {
+				out.Embeddings[start+i].Embedding = vec
+			}
+			out.Usage.TotalTokens += tokens
+		}
+		if sparse {
+			var vectors [][]sparseValue
+			body.Dimensions = nil
+			tokens, err := Do(ctx, e.httpClient, http.MethodPost, e.baseURL+"/embed_sparse", e.cfg.APIKey, body, &vectors)
+			if err != nil {
+				return nil, err
+			}
+			if len(vectors) != end-start {
+				return nil, errors.New("sparse embeddings count mismatch")
+			}
+			for i, vec := range vectors {
+				items := make([]domain.SparseEmbedding, 0, len(vec))
+				for _, v := range vec {
+					items = append(items, domain.SparseEmbedding{Index: v.Index, Value: v.Value})
+				}
+				out.Embeddings[start+i].SparseEmbedding = items
+			}
+			// dense&sparse 时两次请求处理的是同一批文本, 只统计一次
+			if !dense {
+				out.Usage.TotalTokens += tokens
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package tei
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+// embedRoutes max_client_batch_size 为 2, 每个批次的 x-compute-tokens 为 5
+func embedRoutes(t *testing.T) standin.Routes {
+	batch := func(t *testing.T, r *http.Request) []string {
+		var body embedRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Inputs) > 2 || !body.Truncate {
+			t.Errorf("unexpected batch: %+v", body)
+		}
+		return body.Inputs
+	}
+	return standin.Routes{
+		"GET /info": standin.JSON(`{"model_id":"BAAI/bge-m3","max_input_length":8192,"max_client_batch_size":2,"model_type":{"embedding":{}}}`),
+		"POST /embed": func(w http.ResponseWriter, r *http.Request) {
+			inputs := batch(t, r)
+			out := make([][]float64, len(inputs))
+			for i := range out {
+				out[i] = []float64{float64(len(inputs[i])), 0.5}
+			}
+			w.Header().Set("X-Compute-Tokens", "5")
+			standin.WriteJSON(w, out)
+		},
+		"POST /embed_sparse": func(w http.ResponseWriter, r *http.Request) {
+			inputs := batch(t, r)
+			out := make([][]sparseValue, len(inputs))
+			for i := range out {
+				out[i] = []sparseValue{{Index: 7, Value: 0.25}}
+			}
+			w.Header().Set("X-Compute-Tokens", "5")
+			standin.WriteJSON(w, out)
+		},
+	}
+}
+
+func TestEmbedder_BatchesDenseAndSparse(t *testing.T) {
+	ts := standin.New(t, embedRoutes(t))
+	outputType := "dense&sparse"
+	emb, err := NewEmbedder(context.Background(), &EmbeddingConfig{BaseURL: ts.URL + "/v1", OutputType: &outputType})
+	if err != nil {
+		t.Fatalf("NewEmbedder failed: %v", err)
+	}
+	resp, err := emb.(*Embedder).EmbedStringsExt(context.Background(), []string{"a", "bb", "ccc"})
+	if err != nil {
+		t.Fatalf("EmbedStringsExt failed: %v", err)
+	}
+	// 两个批次, dense&sparse 只统计一次
+	if len(resp.Embeddings) != 3 || resp.Usage.TotalTokens != 10 {
+		t.Fatalf("unexpected embeddings: %+v", resp)
+	}
+	last := resp.Embeddings[2]
+	if last.TextIndex != 2 || last.Embedding[0] != 3 || len(last.SparseEmbedding) != 1 || last.SparseEmbedding[0].Index != 7 {
+		t.Fatalf("unexpected embedding item: %+v", last)
+	}
+}
+
+func TestInfoCache_RetriesUntilSuccess(t *testing.T) {
+	var calls int
+	ts := standin.New(t, standin.Routes{
+		"GET /info": func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			standin.JSON(`{"model_id":"BAAI/bge-m3","max_client_batch_size":2}`)(w, r)
+		},
+	})
+
+	var cache InfoCache
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// 首次调用的 ctx 已取消, 不应影响之后的调用
+	if _, err := cache.Get(ctx, http.DefaultClient, ts.URL, ""); err == nil {
+		t.Fatal("expected error with canceled context")
+	}
+	if _, err := cache.Get(context.Background(), http.DefaultClient, ts.URL, ""); err == nil {
+		t.Fatal("expected error from failing /info")
+	}
+	for range 2 {
+		info, err := cache.Get(context.Background(), http.DefaultClient, ts.URL, "")
+		if err != nil || info.MaxClientBatchSize != 2 {
+			t.Fatalf("unexpected info: %+v %v", info, err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected successful /info to be cached, got %d calls", calls)
+	}
+}
@@ -0,0 +1,106 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"google.golang.org/genai"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+// Vertex AI 文本向量单次请求最多 250 条文本
+const maxBatch = 250
+
+type EmbeddingConfig struct {
+	// 需要使用 Vertex AI 后端创建的客户端
+	Client *genai.Client
+	// 模型名称, 例如 text-embedding-005, gemini-embedding-001
+	Model string
+	// 输出向量维度, 映射为 outputDimensionality
+	Dimension *int
+	// 文本类型 query/document, 映射为 RETRIEVAL_QUERY/RETRIEVAL_DOCUMENT, 其余值原样作为 task_type
+	TextType *string
+}
+
+type Embedder struct {
+	cfg *EmbeddingConfig
+}
+
+func NewEmbedder(ctx context.Context, cfg *EmbeddingConfig) (embedding.Embedder, error) {
+	if cfg == nil || cfg.Client == nil || cfg.Model == "" {
+		return nil, errors.New("invalid embedding config")
+	}
+	return &Embedder{cfg: cfg}, nil
+}
+
+func (e *Embedder) taskType() string {
+	if e.cfg.TextType == nil || *e.cfg.TextType == "" {
+		return "RETRIEVAL_DOCUMENT"
+	}
+	switch strings.ToLower(*e.cfg.TextType) {
+	case "query":
+		return "RETRIEVAL_QUERY"
+	case "document":
+		return "RETRIEVAL_DOCUMENT"
+	default:
+		return strings.ToUpper(*e.cfg.TextType)
+	}
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	resp, err := e.EmbedStringsExt(ctx, texts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]float64, 0, len(resp.Embeddings))
+	for _, item := range resp.Embeddings {
+		out = append(out, item.Embedding)
+	}
+	return out, nil
+}
+
+func (e *Embedder) EmbedStringsExt(ctx context.Context, texts []string, opts ...embedding.Option) (*domain.EmbeddingsResponse, error) {
+	if len(texts) == 0 {
+		return nil, errors.New("texts is empty")
+	}
+	cfg := &genai.EmbedContentConfig{
+		TaskType:     e.taskType(),
+		AutoTruncate: true,
+	}
+	if e.cfg.Dimension != nil && *e.cfg.Dimension > 0 {
+		dim := int32(*e.cfg.Dimension)
+		cfg.OutputDimensionality = &dim
+	}
+
+	out := &domain.EmbeddingsResponse{
+		Embeddings: make([]domain.EmbeddingItem, 0, len(texts)),
+	}
+	for start := 0; start < len(texts); start += maxBatch {
+		end := min(start+maxBatch, len(texts))
+		contents := make([]*genai.Content, 0, end-start)
+		for _, text := range texts[start:end] {
+			contents = append(contents, genai.NewContentFromText(text, genai.RoleUser))
+		}
+		resp, err := e.cfg.Client.Models.EmbedContent(ctx, e.cfg.Model, contents, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Embeddings) != len(contents) {
+			return nil, errors.New("embeddings count mismatch")
+		}
+		for i, emb := range resp.Embeddings {
+			vec := make([]float64, len(emb.Values))
+			for j, v := range emb.Values {
+				vec[j] = float64(v)
+			}
+			out.Embeddings = append(out.Embeddings, domain.EmbeddingItem{Embedding: vec, TextIndex: start + i})
+			if emb.Statistics != nil {
+				out.Usage.TotalTokens += int(emb.Statistics.TokenCount)
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+package vertex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"cloud.google.com/go/auth"
+	"google.golang.org/genai"
+
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+type staticToken string
+
+func (s staticToken) Token(context.Context) (*auth.Token, error) {
+	return &auth.Token{Value: string(s), Type: "Bearer"}, nil
+}
+
+func TestEmbedder_TaskTypeAndUsage(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"POST /v1beta1/projects/p/locations/us-central1/publishers/google/models/text-embedding-005:predict": func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Instances []map[string]any `json:"instances"`
+				Params    map[string]any   `json:"parameters"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Instances) != 2 || body.Instances[0]["task_type"] != "RETRIEVAL_QUERY" ||
+				body.Params["outputDimensionality"] != float64(256) || body.Params["autoTruncate"] != true {
+				t.Errorf("unexpected request: %+v", body)
+			}
+			standin.JSON(`{"predictions": [
+				{"embeddings": {"values": [0.1, 0.2], "statistics": {"token_count": 3, "truncated": false}}},
+				{"embeddings": {"values": [0.3, 0.4], "statistics": {"token_count": 5, "truncated": false}}}
+			]}`)(w, r)
+		},
+	}, standin.Header("Authorization", "Bearer test-token"))
+
+	ctx := context.Background()
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		Backend:     genai.BackendVertexAI,
+		Project:     "p",
+		Location:    "us-central1",
+		Credentials: auth.NewCredentials(&auth.CredentialsOptions{TokenProvider: staticToken("test-token")}),
+		HTTPOptions: genai.HTTPOptions{BaseURL: ts.URL + "/"},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	dim := 256
+	textType := "query"
+	emb, err := NewEmbedder(ctx, &EmbeddingConfig{Client: client, Model: "text-embedding-005", Dimension: &dim, TextType: &textType})
+	if err != nil {
+		t.Fatalf("NewEmbedder failed: %v", err)
+	}
+	resp, err := emb.(*Embedder).EmbedStringsExt(ctx, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("EmbedStringsExt failed: %v", err)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[1].TextIndex != 1 || resp.Embeddings[1].Embedding[1] < 0.39 || resp.Usage.TotalTokens != 8 {
+		t.Fatalf("unexpected embeddings: %+v", resp)
+	}
+}
@@ -0,0 +1,172 @@
+package voyage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/eino/components/embedding"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+const (
+	defaultBaseURL = "https://api.voyageai.com/v1"
+	// 单次请求最多 1000 条文本
+	maxBatch = 1000
+)
+
+type EmbeddingConfig struct {
+	APIKey string
+	Model  string
+	// 默认 https://api.voyageai.com/v1, 以 # 结尾时原样作为请求地址
+	BaseURL    string
+	HTTPClient *http.Client
+	// 输出向量维度, voyage-3.5/voyage-3-large 等支持 256/512/1024/2048
+	Dimension *int
+	// 文本类型 query/document, 映射为 input_type, 为空时不区分
+	TextType *string
+	// 输出数据类型 float/int8/uint8/binary/ubinary, 量化结果按整数值写入 Embedding
+	OutputDtype *string
+}
+
+type Embedder struct {
+	cfg        *EmbeddingConfig
+	httpClient *http.Client
+	endpoint   string
+}
+
+func NewEmbedder(ctx context.Context, cfg *EmbeddingConfig) (embedding.Embedder, error) {
+	if cfg == nil || cfg.Model == "" || cfg.APIKey == "" {
+		return nil, errors.New("invalid embedding config")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Embedder{
+		cfg:        cfg,
+		httpClient: httpClient,
+		endpoint:   normalizeEndpoint(cfg.BaseURL),
+	}, nil
+}
+
+func normalizeEndpoint(u string) string {
+	if strings.HasSuffix(u, "#") {
+		return strings.TrimSuffix(u, "#")
+	}
+	if u == "" {
+		u = defaultBaseURL
+	}
+	u = strings.TrimSuffix(u, "/")
+	if !strings.HasSuffix(u, "/embeddings") {
+		return u + "/embeddings"
+	}
+	return u
+}
+
+type apiRequest struct {
+	Model           string   `json:"model"`
+	Input           []string `json:"input"`
+	InputType       *string  `json:"input_type"`
+	Truncation      bool     `json:"truncation"`
+	OutputDimension *int     `json:"output_dimension,omitempty"`
+	OutputDtype     string   `json:"output_dtype,omitempty"`
+}
+
+type apiResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	resp, err := e.EmbedStringsExt(ctx, texts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]float64, 0, len(resp.Embeddings))
+	for _, item := range resp.Embeddings {
+		out = append(out, item.Embedding)
+	}
+	return out, nil
+}
+
+func (e *Embedder) EmbedStringsExt(ctx context.Context, texts []string, opts ...embedding.Option) (*domain.EmbeddingsResponse, error) {
+	if len(texts) == 0 {
+		return nil, errors.New("texts is empty")
+	}
+	body := apiRequest{
+		Model:           e.cfg.Model,
+		Truncation:      true,
+		OutputDimension: e.cfg.Dimension,
+	}
+	if e.cfg.TextType != nil && *e.cfg.TextType != "" {
+		inputType := strings.ToLower(*e.cfg.TextType)
+		body.InputType = &inputType
+	}
+	if e.cfg.OutputDtype != nil {
+		body.OutputDtype = strings.ToLower(*e.cfg.OutputDtype)
+	}
+
+	out := &domain.EmbeddingsResponse{
+		Embeddings: make([]domain.EmbeddingItem, 0, len(texts)),
+	}
+	for start := 0; start < len(texts); start += maxBatch {
+		end := min(start+maxBatch, len(texts))
+		body.Input = texts[start:end]
+		ar, err := e.do(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		if len(ar.Data) != end-start {
+			return nil, errors.New("embeddings count mismatch")
+		}
+		for _, d := range ar.Data {
+			out.Embeddings = append(out.Embeddings, domain.EmbeddingItem{Embedding: d.Embedding, TextIndex: start + d.Index})
+		}
+		out.Usage.TotalTokens += ar.Usage.TotalTokens
+	}
+	return out, nil
+}
+
+func (e *Embedder) do(ctx context.Context, body apiRequest) (*apiResponse, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var ar apiResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&ar)
+	if resp.StatusCode != http.StatusOK {
+		if ar.Detail != "" {
+			return nil, fmt.Errorf("%s: %s", resp.Status, ar.Detail)
+		}
+		return nil, errors.New(resp.Status)
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return &ar, nil
+}
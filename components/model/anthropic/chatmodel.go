@@ -0,0 +1,246 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultBaseURL = "https://api.anthropic.com/v1"
+	// DefaultAPIVersion anthropic-version 请求头默认值
+	DefaultAPIVersion = "2023-06-01"
+	// Messages API 要求必须指定 max_tokens
+	defaultMaxTokens = 4096
+)
+
+type ChatModelConfig struct {
+	APIKey  string
+	Model   string
+	BaseURL string
+	// anthropic-version 请求头, 默认 2023-06-01
+	APIVersion string
+	HTTPClient *http.Client
+
+	MaxTokens     *int
+	Temperature   *float32
+	TopP          *float32
+	TopK          *int
+	StopSequences []string
+	// 开启 extended thinking 时的思考 token 预算, 至少 1024 且需小于 MaxTokens, 未设置 MaxTokens 时自动为预算加默认长度
+	ThinkingBudget *int
+	// 结构化输出的 JSON Schema, 设置后追加一个以其为入参的输出工具,
+	// 模型调用该工具的入参作为 Content 返回
+	ResponseSchema any
+}
+
+type ChatModel struct {
+	cfg        *ChatModelConfig
+	httpClient *http.Client
+	endpoint   string
+	tools      []*schema.ToolInfo
+}
+
+func NewChatModel(ctx context.Context, cfg *ChatModelConfig) (*ChatModel, error) {
+	if cfg == nil || cfg.Model == "" {
+		return nil, errors.New("invalid chat model config")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &ChatModel{
+		cfg:        cfg,
+		httpClient: httpClient,
+		endpoint:   NormalizeBaseURL(cfg.BaseURL) + "/messages",
+	}, nil
+}
+
+// NormalizeBaseURL 统一为以 /v1 结尾的地址, 以#结尾时原样使用
+func NormalizeBaseURL(u string) string {
+	if u == "" {
+		return defaultBaseURL
+	}
+	if strings.HasSuffix(u, "#") {
+		return strings.TrimSuffix(u, "#")
+	}
+	u = strings.TrimSuffix(u, "/")
+	if !strings.HasSuffix(u, "/v1") {
+		u += "/v1"
+	}
+	return u
+}
+
+// SetHeaders 设置 Anthropic 鉴权及版本请求头
+func SetHeaders(h http.Header, apiKey, apiVersion string) {
+	if apiVersion == "" {
+		apiVersion = DefaultAPIVersion
+	}
+	h.Set("x-api-key", apiKey)
+	h.Set("anthropic-version", apiVersion)
+}
+
+func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	reqBody, err := cm.buildRequest(input, false, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cm.do(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var mr messageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&mr); err != nil {
+		return nil, err
+	}
+	return toSchemaMessage(&mr)
+}
+
+func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	reqBody, err := cm.buildRequest(input, true, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cm.do(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			_ = resp.Body.Close()
+			sw.Close()
+		}()
+		readStream(resp.Body, sw)
+	}()
+	return sr, nil
+}
+
+// WithTools 返回绑定了工具的新实例, 不修改当前实例
+func (cm *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	if len(tools) == 0 {
+		return nil, errors.New("no tools to bind")
+	}
+	ncm := *cm
+	ncm.tools = tools
+	return &ncm, nil
+}
+
+func (cm *ChatModel) GetType() string {
+	return "Anthropic"
+}
+
+func (cm *ChatModel) do(ctx context.Context, body *messageRequest) (*http.Response, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, cm.endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	SetHeaders(httpReq.Header, cm.cfg.APIKey, cm.cfg.APIVersion)
+	if body.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	resp, err := cm.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		b, _ := io.ReadAll(resp.Body)
+		var er errorResponse
+		if json.Unmarshal(b, &er) == nil && er.Error.Message != "" {
+			return nil, fmt.Errorf("%s: %s: %s", resp.Status, er.Error.Type, er.Error.Message)
+		}
+		return nil, errors.New(resp.Status)
+	}
+	return resp, nil
+}
+
+func (cm *ChatModel) buildRequest(input []*schema.Message, stream bool, opts ...model.Option) (*messageRequest, error) {
+	maxTokens := defaultMaxTokens
+	if cm.cfg.MaxTokens != nil {
+		maxTokens = *cm.cfg.MaxTokens
+	} else if cm.cfg.ThinkingBudget != nil && *cm.cfg.ThinkingBudget > 0 {
+		// max_tokens 包含思考预算且必须大于预算, 未设置时在预算之外保留默认的回答长度
+		maxTokens = *cm.cfg.ThinkingBudget + defaultMaxTokens
+	}
+	modelName := cm.cfg.Model
+	options := model.GetCommonOptions(&model.Options{
+		Temperature: cm.cfg.Temperature,
+		MaxTokens:   &maxTokens,
+		Model:       &modelName,
+		TopP:        cm.cfg.TopP,
+		Stop:        cm.cfg.StopSequences,
+		Tools:       cm.tools,
+	}, opts...)
+
+	system, msgs, err := convertMessages(input)
+	if err != nil {
+		return nil, err
+	}
+	req := &messageRequest{
+		Model:         *options.Model,
+		MaxTokens:     *options.MaxTokens,
+		System:        system,
+		Messages:      msgs,
+		StopSequences: options.Stop,
+		TopK:          cm.cfg.TopK,
+		Stream:        stream,
+	}
+	if cm.cfg.ThinkingBudget != nil && *cm.cfg.ThinkingBudget > 0 {
+		req.Thinking = &thinkingConfig{Type: "enabled", BudgetTokens: *cm.cfg.ThinkingBudget}
+	} else {
+		// extended thinking 开启时不允许设置 temperature 与 top_p
+		req.Temperature = options.Temperature
+		req.TopP = options.TopP
+	}
+
+	if len(options.Tools) > 0 {
+		tools, err := convertTools(options.Tools)
+		if err != nil {
+			return nil, err
+		}
+		req.Tools = tools
+	}
+	if options.ToolChoice != nil {
+		switch *options.ToolChoice {
+		case schema.ToolChoiceForbidden:
+			req.ToolChoice = &toolChoice{Type: "none"}
+		case schema.ToolChoiceForced:
+			req.ToolChoice = &toolChoice{Type: "any"}
+			if len(req.Tools) == 1 {
+				req.ToolChoice = &toolChoice{Type: "tool", Name: req.Tools[0].Name}
+			}
+		default:
+			req.ToolChoice = &toolChoice{Type: "auto"}
+		}
+	}
+	if cm.cfg.ResponseSchema != nil {
+		req.Tools = append(req.Tools, tool{
+			Name:        responseToolName,
+			Description: "Respond to the user with JSON that matches the input schema.",
+			InputSchema: cm.cfg.ResponseSchema,
+		})
+		// extended thinking 不允许强制调用工具, 绑定了其他工具时也交由模型选择
+		if req.ToolChoice == nil && len(options.Tools) == 0 && req.Thinking == nil {
+			req.ToolChoice = &toolChoice{Type: "tool", Name: responseToolName}
+		}
+	}
+	return req, nil
+}
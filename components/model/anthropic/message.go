@@ -0,0 +1,299 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ExtraKeyThinkingSignature thinking 块签名在 schema.Message.Extra 中的键,
+// 多轮工具调用时需要原样回传给 Anthropic
+const ExtraKeyThinkingSignature = "anthropic_thinking_signature"
+
+// responseToolName 结构化输出使用的工具名, 其调用结果转换为 Content
+const responseToolName = "json_response"
+
+type messageRequest struct {
+	Model         string          `json:"model"`
+	MaxTokens     int             `json:"max_tokens"`
+	System        string          `json:"system,omitempty"`
+	Messages      []message       `json:"messages"`
+	Temperature   *float32        `json:"temperature,omitempty"`
+	TopP          *float32        `json:"top_p,omitempty"`
+	TopK          *int            `json:"top_k,omitempty"`
+	StopSequences []string        `json:"stop_sequences,omitempty"`
+	Stream        bool            `json:"stream,omitempty"`
+	Thinking      *thinkingConfig `json:"thinking,omitempty"`
+	Tools         []tool          `json:"tools,omitempty"`
+	ToolChoice    *toolChoice     `json:"tool_choice,omitempty"`
+}
+
+type thinkingConfig struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+type tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
+}
+
+type toolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type message struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+type contentBlock struct {
+	Type string `json:"type"`
+	// text
+	Text string `json:"text,omitempty"`
+	// image
+	Source *imageSource `json:"source,omitempty"`
+	// tool_use
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+	// tool_result
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	// thinking
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+type imageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+type messageResponse struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Role       string         `json:"role"`
+	Model      string         `json:"model"`
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      usage          `json:"usage"`
+}
+
+type usage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+type errorResponse struct {
+	Type  string `json:"type"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// convertMessages 将 eino 消息转换为 Messages API 格式,
+// system 消息合并为顶层 system 字段, 连续同角色消息合并为一条
+func convertMessages(input []*schema.Message) (string, []message, error) {
+	var systems []string
+	msgs := make([]message, 0, len(input))
+	for _, in := range input {
+		if in == nil {
+			continue
+		}
+		var role string
+		var blocks []contentBlock
+		switch in.Role {
+		case schema.System:
+			systems = append(systems, in.Content)
+			continue
+		case schema.User:
+			role = "user"
+			b, err := userBlocks(in)
+			if err != nil {
+				return "", nil, err
+			}
+			blocks = b
+		case schema.Assistant:
+			role = "assistant"
+			blocks = assistantBlocks(in)
+		case schema.Tool:
+			role = "user"
+			blocks = []contentBlock{{Type: "tool_result", ToolUseID: in.ToolCallID, Content: in.Content}}
+		default:
+			return "", nil, fmt.Errorf("unknown role: %s", in.Role)
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+		if n := len(msgs); n > 0 && msgs[n-1].Role == role {
+			msgs[n-1].Content = append(msgs[n-1].Content, blocks...)
+			continue
+		}
+		msgs = append(msgs, message{Role: role, Content: blocks})
+	}
+	return strings.Join(systems, "\n"), msgs, nil
+}
+
+func userBlocks(in *schema.Message) ([]contentBlock, error) {
+	if len(in.UserInputMultiContent) == 0 {
+		if in.Content == "" {
+			return nil, nil
+		}
+		return []contentBlock{{Type: "text", Text: in.Content}}, nil
+	}
+	blocks := make([]contentBlock, 0, len(in.UserInputMultiContent))
+	for _, part := range in.UserInputMultiContent {
+		switch part.Type {
+		case schema.ChatMessagePartTypeText:
+			blocks = append(blocks, contentBlock{Type: "text", Text: part.Text})
+		case schema.ChatMessagePartTypeImageURL:
+			if part.Image == nil {
+				continue
+			}
+			src, err := toImageSource(&part.Image.MessagePartCommon)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, contentBlock{Type: "image", Source: src})
+		default:
+			return nil, fmt.Errorf("unsupported content type: %s", part.Type)
+		}
+	}
+	return blocks, nil
+}
+
+func toImageSource(p *schema.MessagePartCommon) (*imageSource, error) {
+	if p.Base64Data != nil {
+		return &imageSource{Type: "base64", MediaType: p.MIMEType, Data: *p.Base64Data}, nil
+	}
+	if p.URL == nil {
+		return nil, errors.New("image url is empty")
+	}
+	u := *p.URL
+	// data:image/png;base64,xxxx
+	if rest, ok := strings.CutPrefix(u, "data:"); ok {
+		meta, data, found := strings.Cut(rest, ",")
+		if !found {
+			return nil, errors.New("invalid data url")
+		}
+		return &imageSource{Type: "base64", MediaType: strings.TrimSuffix(meta, ";base64"), Data: data}, nil
+	}
+	return &imageSource{Type: "url", URL: u}, nil
+}
+
+func assistantBlocks(in *schema.Message) []contentBlock {
+	var blocks []contentBlock
+	if in.ReasoningContent != "" {
+		if sig, ok := in.Extra[ExtraKeyThinkingSignature].(string); ok && sig != "" {
+			blocks = append(blocks, contentBlock{Type: "thinking", Thinking: in.ReasoningContent, Signature: sig})
+		}
+	}
+	if in.Content != "" {
+		blocks = append(blocks, contentBlock{Type: "text", Text: in.Content})
+	}
+	for _, tc := range in.ToolCalls {
+		args := json.RawMessage(tc.Function.Arguments)
+		if len(args) == 0 || !json.Valid(args) {
+			args = json.RawMessage("{}")
+		}
+		blocks = append(blocks, contentBlock{Type: "tool_use", ID: tc.ID, Name: tc.Function.Name, Input: args})
+	}
+	return blocks
+}
+
+func convertTools(tools []*schema.ToolInfo) ([]tool, error) {
+	out := make([]tool, 0, len(tools))
+	for _, ti := range tools {
+		if ti == nil {
+			continue
+		}
+		js, err := ti.ToJSONSchema()
+		if err != nil {
+			return nil, fmt.Errorf("convert tool %s schema failed: %w", ti.Name, err)
+		}
+		var inputSchema any = js
+		if js == nil {
+			inputSchema = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		out = append(out, tool{Name: ti.Name, Description: ti.Desc, InputSchema: inputSchema})
+	}
+	return out, nil
+}
+
+func toSchemaMessage(mr *messageResponse) (*schema.Message, error) {
+	out := &schema.Message{
+		Role: schema.Assistant,
+		ResponseMeta: &schema.ResponseMeta{
+			FinishReason: mr.StopReason,
+			Usage:        toTokenUsage(mr.Usage),
+		},
+	}
+	var text, reasoning strings.Builder
+	for _, b := range mr.Content {
+		switch b.Type {
+		case "text":
+			text.WriteString(b.Text)
+		case "thinking":
+			reasoning.WriteString(b.Thinking)
+			if b.Signature != "" {
+				setExtra(out, ExtraKeyThinkingSignature, b.Signature)
+			}
+		case "tool_use":
+			if b.Name == responseToolName {
+				text.Write(b.Input)
+				continue
+			}
+			idx := len(out.ToolCalls)
+			args := string(b.Input)
+			if args == "" {
+				args = "{}"
+			}
+			out.ToolCalls = append(out.ToolCalls, schema.ToolCall{
+				Index:    &idx,
+				ID:       b.ID,
+				Type:     "function",
+				Function: schema.FunctionCall{Name: b.Name, Arguments: args},
+			})
+		}
+	}
+	out.Content = text.String()
+	out.ReasoningContent = reasoning.String()
+	out.ResponseMeta.FinishReason = finishReason(mr.StopReason, len(out.ToolCalls) > 0)
+	return out, nil
+}
+
+// finishReason 仅调用了输出工具时视为正常结束
+func finishReason(stopReason string, hasToolCalls bool) string {
+	if stopReason == "tool_use" && !hasToolCalls {
+		return "end_turn"
+	}
+	return stopReason
+}
+
+func toTokenUsage(u usage) *schema.TokenUsage {
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return &schema.TokenUsage{
+		PromptTokens:       prompt,
+		PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: u.CacheReadInputTokens},
+		CompletionTokens:   u.OutputTokens,
+		TotalTokens:        prompt + u.OutputTokens,
+	}
+}
+
+func setExtra(m *schema.Message, key string, value any) {
+	if m.Extra == nil {
+		m.Extra = map[string]any{}
+	}
+	m.Extra[key] = value
+}
@@ -0,0 +1,152 @@
+package anthropic
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type streamEvent struct {
+	Type         string           `json:"type"`
+	Index        int              `json:"index"`
+	Message      *messageResponse `json:"message,omitempty"`
+	ContentBlock *contentBlock    `json:"content_block,omitempty"`
+	Delta        *streamDelta     `json:"delta,omitempty"`
+	Usage        *usage           `json:"usage,omitempty"`
+	Error        *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type streamDelta struct {
+	Type        string `json:"type"`
+	Text        string `json:"text,omitempty"`
+	PartialJSON string `json:"partial_json,omitempty"`
+	Thinking    string `json:"thinking,omitempty"`
+	Signature   string `json:"signature,omitempty"`
+	StopReason  string `json:"stop_reason,omitempty"`
+}
+
+// readStream 解析 SSE 事件并逐块写入 sw
+func readStream(body io.Reader, sw *schema.StreamWriter[*schema.Message]) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	var inputUsage usage
+	// content block index -> tool call 序号
+	toolIndex := map[int]int{}
+	// 结构化输出工具所在的 content block index
+	outputIndex := map[int]bool{}
+
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "" {
+			continue
+		}
+		var ev streamEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			sw.Send(nil, fmt.Errorf("decode stream event failed: %w", err))
+			return
+		}
+
+		var chunk *schema.Message
+		switch ev.Type {
+		case "message_start":
+			if ev.Message != nil {
+				inputUsage = ev.Message.Usage
+			}
+		case "content_block_start":
+			if ev.ContentBlock != nil && ev.ContentBlock.Type == "tool_use" {
+				if ev.ContentBlock.Name == responseToolName {
+					outputIndex[ev.Index] = true
+					break
+				}
+				idx := len(toolIndex)
+				toolIndex[ev.Index] = idx
+				chunk = &schema.Message{
+					Role: schema.Assistant,
+					ToolCalls: []schema.ToolCall{{
+						Index:    &idx,
+						ID:       ev.ContentBlock.ID,
+						Type:     "function",
+						Function: schema.FunctionCall{Name: ev.ContentBlock.Name},
+					}},
+				}
+			}
+		case "content_block_delta":
+			chunk = deltaToMessage(&ev, toolIndex, outputIndex)
+		case "message_delta":
+			u := inputUsage
+			if ev.Usage != nil {
+				u.OutputTokens = ev.Usage.OutputTokens
+			}
+			chunk = &schema.Message{
+				Role:         schema.Assistant,
+				ResponseMeta: &schema.ResponseMeta{Usage: toTokenUsage(u)},
+			}
+			if ev.Delta != nil {
+				chunk.ResponseMeta.FinishReason = finishReason(ev.Delta.StopReason, len(toolIndex) > 0)
+			}
+		case "error":
+			msg := "stream error"
+			if ev.Error != nil {
+				msg = ev.Error.Type + ": " + ev.Error.Message
+			}
+			sw.Send(nil, errors.New(msg))
+			return
+		case "message_stop":
+			return
+		}
+		if chunk != nil {
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		sw.Send(nil, err)
+	}
+}
+
+func deltaToMessage(ev *streamEvent, toolIndex map[int]int, outputIndex map[int]bool) *schema.Message {
+	if ev.Delta == nil {
+		return nil
+	}
+	switch ev.Delta.Type {
+	case "text_delta":
+		return &schema.Message{Role: schema.Assistant, Content: ev.Delta.Text}
+	case "thinking_delta":
+		return &schema.Message{Role: schema.Assistant, ReasoningContent: ev.Delta.Thinking}
+	case "signature_delta":
+		m := &schema.Message{Role: schema.Assistant}
+		setExtra(m, ExtraKeyThinkingSignature, ev.Delta.Signature)
+		return m
+	case "input_json_delta":
+		if outputIndex[ev.Index] {
+			return &schema.Message{Role: schema.Assistant, Content: ev.Delta.PartialJSON}
+		}
+		idx, ok := toolIndex[ev.Index]
+		if !ok || ev.Delta.PartialJSON == "" {
+			return nil
+		}
+		return &schema.Message{
+			Role: schema.Assistant,
+			ToolCalls: []schema.ToolCall{{
+				Index:    &idx,
+				Function: schema.FunctionCall{Arguments: ev.Delta.PartialJSON},
+			}},
+		}
+	default:
+		return nil
+	}
+}
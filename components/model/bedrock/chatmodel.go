@@ -0,0 +1,211 @@
+package bedrock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/pkg/sigv4"
+)
+
+type ChatModelConfig struct {
+	Model string
+	// bedrock-runtime 地址, 例如 https://bedrock-runtime.us-east-1.amazonaws.com
+	BaseURL string
+	// 需要已配置 SigV4 签名(或 Bedrock API Key)的客户端
+	HTTPClient *http.Client
+
+	MaxTokens     *int
+	Temperature   *float32
+	TopP          *float32
+	StopSequences []string
+	// 透传给底层模型的额外字段, 例如 {"top_k": 50}
+	AdditionalModelRequestFields map[string]any
+	// 结构化输出的 JSON Schema, 设置后追加一个以其为入参的输出工具,
+	// 模型调用该工具的入参作为 Content 返回
+	ResponseSchema any
+}
+
+type ChatModel struct {
+	cfg        *ChatModelConfig
+	httpClient *http.Client
+	baseURL    string
+	tools      []*schema.ToolInfo
+}
+
+func NewChatModel(ctx context.Context, cfg *ChatModelConfig) (*ChatModel, error) {
+	if cfg == nil || cfg.Model == "" || cfg.BaseURL == "" {
+		return nil, errors.New("invalid chat model config")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &ChatModel{
+		cfg:        cfg,
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(strings.TrimSuffix(cfg.BaseURL, "#"), "/"),
+	}, nil
+}
+
+func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	modelName, reqBody, err := cm.buildRequest(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cm.do(ctx, modelName, "converse", reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var cr converseResponse
+	if err := json.NewDecoder(resp.Body).Decode(&cr); err != nil {
+		return nil, err
+	}
+	return toSchemaMessage(&cr), nil
+}
+
+func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	modelName, reqBody, err := cm.buildRequest(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cm.do(ctx, modelName, "converse-stream", reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			_ = resp.Body.Close()
+			sw.Close()
+		}()
+		readStream(resp.Body, sw)
+	}()
+	return sr, nil
+}
+
+// WithTools 返回绑定了工具的新实例, 不修改当前实例
+func (cm *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	if len(tools) == 0 {
+		return nil, errors.New("no tools to bind")
+	}
+	ncm := *cm
+	ncm.tools = tools
+	return &ncm, nil
+}
+
+func (cm *ChatModel) GetType() string {
+	return "Bedrock"
+}
+
+func (cm *ChatModel) do(ctx context.Context, modelName, action string, body *converseRequest) (*http.Response, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := cm.baseURL + "/model/" + sigv4.EscapePathSegment(modelName) + "/" + action
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := cm.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, ParseError(resp)
+	}
+	return resp, nil
+}
+
+// ParseError 解析 Bedrock 错误响应
+func ParseError(resp *http.Response) error {
+	b, _ := io.ReadAll(resp.Body)
+	var er struct {
+		Message  string `json:"message"`
+		MessageU string `json:"Message"`
+	}
+	errType := resp.Header.Get("X-Amzn-Errortype")
+	if errType != "" {
+		errType, _, _ = strings.Cut(errType, ":")
+	}
+	if json.Unmarshal(b, &er) == nil {
+		msg := er.Message
+		if msg == "" {
+			msg = er.MessageU
+		}
+		if msg != "" {
+			if errType != "" {
+				return fmt.Errorf("%s: %s: %s", resp.Status, errType, msg)
+			}
+			return fmt.Errorf("%s: %s", resp.Status, msg)
+		}
+	}
+	return errors.New(resp.Status)
+}
+
+func (cm *ChatModel) buildRequest(input []*schema.Message, opts ...model.Option) (string, *converseRequest, error) {
+	modelName := cm.cfg.Model
+	options := model.GetCommonOptions(&model.Options{
+		Temperature: cm.cfg.Temperature,
+		MaxTokens:   cm.cfg.MaxTokens,
+		Model:       &modelName,
+		TopP:        cm.cfg.TopP,
+		Stop:        cm.cfg.StopSequences,
+		Tools:       cm.tools,
+	}, opts...)
+
+	system, msgs, err := convertMessages(input)
+	if err != nil {
+		return "", nil, err
+	}
+	req := &converseRequest{
+		Messages:                     msgs,
+		System:                       system,
+		AdditionalModelRequestFields: cm.cfg.AdditionalModelRequestFields,
+	}
+	if options.MaxTokens != nil || options.Temperature != nil || options.TopP != nil || len(options.Stop) > 0 {
+		req.InferenceConfig = &inferenceConfig{
+			MaxTokens:     options.MaxTokens,
+			Temperature:   options.Temperature,
+			TopP:          options.TopP,
+			StopSequences: options.Stop,
+		}
+	}
+
+	if len(options.Tools) > 0 {
+		tc, err := convertTools(options.Tools, options.ToolChoice)
+		if err != nil {
+			return "", nil, err
+		}
+		req.ToolConfig = tc
+	}
+	if cm.cfg.ResponseSchema != nil {
+		if req.ToolConfig == nil {
+			req.ToolConfig = &toolConfig{}
+		}
+		spec := toolSpec{Name: responseToolName, Description: "Respond to the user with JSON that matches the input schema."}
+		spec.InputSchema.JSON = cm.cfg.ResponseSchema
+		req.ToolConfig.Tools = append(req.ToolConfig.Tools, toolSpecWrapper{ToolSpec: spec})
+		// 绑定了其他工具时交由模型选择
+		if len(options.Tools) == 0 {
+			req.ToolConfig.ToolChoice = map[string]any{"tool": map[string]any{"name": responseToolName}}
+		}
+	}
+	return *options.Model, req, nil
+}
@@ -0,0 +1,98 @@
+package bedrock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+	"github.com/chaitin/ModelKit/v2/pkg/sigv4"
+)
+
+var testCredentials = sigv4.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+// newSignedClient 与 usecase 一致, 使用 SigV4 签名的客户端
+func newSignedClient(t *testing.T) *http.Client {
+	signer, err := sigv4.NewSigner(testCredentials, "us-west-2", "bedrock")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	return &http.Client{Transport: sigv4.NewTransport(signer, http.DefaultTransport)}
+}
+
+func TestChatModel_ConverseAndStream(t *testing.T) {
+	modelID := "anthropic.claude-3-haiku-20240307-v1:0"
+	escapedPath := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			// 与 AWS SDK 一致, 请求行中的 ':' 编码为 %3A
+			if !strings.Contains(r.URL.EscapedPath(), "v1%3A0") {
+				t.Errorf("model id not escaped: %s", r.URL.EscapedPath())
+			}
+			next(w, r)
+		}
+	}
+	ts := standin.New(t, standin.Routes{
+		"/model/" + modelID + "/converse": escapedPath(standin.JSON(`{
+			"output": {"message": {"role": "assistant", "content": [{"text": "Hello there!"}]}},
+			"stopReason": "end_turn",
+			"usage": {"inputTokens": 10, "outputTokens": 5, "totalTokens": 15}
+		}`)),
+		"/model/" + modelID + "/converse-stream": escapedPath(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+			_, _ = w.Write(standin.EventMessage("messageStart", `{"role":"assistant"}`))
+			_, _ = w.Write(standin.EventMessage("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"Hel"}}`))
+			_, _ = w.Write(standin.EventMessage("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"lo"}}`))
+			_, _ = w.Write(standin.EventMessage("contentBlockStart", `{"contentBlockIndex":1,"start":{"toolUse":{"toolUseId":"t1","name":"get_weather"}}}`))
+			_, _ = w.Write(standin.EventMessage("contentBlockDelta", `{"contentBlockIndex":1,"delta":{"toolUse":{"input":"{\"city\":\"Paris\"}"}}}`))
+			_, _ = w.Write(standin.EventMessage("messageStop", `{"stopReason":"tool_use"}`))
+			_, _ = w.Write(standin.EventMessage("metadata", `{"usage":{"inputTokens":10,"outputTokens":7,"totalTokens":17}}`))
+		}),
+	}, standin.SigV4(testCredentials, "us-west-2", "bedrock"))
+
+	ctx := context.Background()
+	cm, err := NewChatModel(ctx, &ChatModelConfig{Model: modelID, BaseURL: ts.URL, HTTPClient: newSignedClient(t)})
+	if err != nil {
+		t.Fatalf("NewChatModel failed: %v", err)
+	}
+	msgs := []*schema.Message{schema.SystemMessage("You are a helpful assistant."), schema.UserMessage("hi")}
+
+	resp, err := cm.Generate(ctx, msgs)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if resp.Content != "Hello there!" || resp.ResponseMeta.Usage.TotalTokens != 15 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	sr, err := cm.Stream(ctx, msgs)
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	defer sr.Close()
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	full, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		t.Fatalf("ConcatMessages failed: %v", err)
+	}
+	if full.Content != "Hello" || len(full.ToolCalls) != 1 || full.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Fatalf("unexpected stream result: %+v", full)
+	}
+	if full.ResponseMeta.FinishReason != "tool_use" || full.ResponseMeta.Usage.TotalTokens != 17 {
+		t.Fatalf("unexpected response meta: %+v", full.ResponseMeta)
+	}
+}
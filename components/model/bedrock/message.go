@@ -0,0 +1,331 @@
+package bedrock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ExtraKeyReasoningSignature reasoningContent 签名在 schema.Message.Extra 中的键
+const ExtraKeyReasoningSignature = "bedrock_reasoning_signature"
+
+// responseToolName 结构化输出使用的工具名, 其调用结果转换为 Content
+const responseToolName = "json_response"
+
+type converseRequest struct {
+	Messages                     []message        `json:"messages"`
+	System                       []systemBlock    `json:"system,omitempty"`
+	InferenceConfig              *inferenceConfig `json:"inferenceConfig,omitempty"`
+	ToolConfig                   *toolConfig      `json:"toolConfig,omitempty"`
+	AdditionalModelRequestFields map[string]any   `json:"additionalModelRequestFields,omitempty"`
+}
+
+type systemBlock struct {
+	Text string `json:"text"`
+}
+
+type inferenceConfig struct {
+	MaxTokens     *int     `json:"maxTokens,omitempty"`
+	Temperature   *float32 `json:"temperature,omitempty"`
+	TopP          *float32 `json:"topP,omitempty"`
+	StopSequences []string `json:"stopSequences,omitempty"`
+}
+
+type toolConfig struct {
+	Tools      []toolSpecWrapper `json:"tools"`
+	ToolChoice map[string]any    `json:"toolChoice,omitempty"`
+}
+
+type toolSpecWrapper struct {
+	ToolSpec toolSpec `json:"toolSpec"`
+}
+
+type toolSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema struct {
+		JSON any `json:"json"`
+	} `json:"inputSchema"`
+}
+
+type message struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+type contentBlock struct {
+	Text             *string           `json:"text,omitempty"`
+	Image            *imageBlock       `json:"image,omitempty"`
+	ToolUse          *toolUseBlock     `json:"toolUse,omitempty"`
+	ToolResult       *toolResultBlock  `json:"toolResult,omitempty"`
+	ReasoningContent *reasoningContent `json:"reasoningContent,omitempty"`
+}
+
+type imageBlock struct {
+	Format string `json:"format"`
+	Source struct {
+		// base64 编码的图片内容
+		Bytes string `json:"bytes"`
+	} `json:"source"`
+}
+
+type toolUseBlock struct {
+	ToolUseID string          `json:"toolUseId"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+}
+
+type toolResultBlock struct {
+	ToolUseID string         `json:"toolUseId"`
+	Content   []contentBlock `json:"content"`
+}
+
+type reasoningContent struct {
+	ReasoningText *reasoningText `json:"reasoningText,omitempty"`
+}
+
+type reasoningText struct {
+	Text      string `json:"text"`
+	Signature string `json:"signature,omitempty"`
+}
+
+type converseResponse struct {
+	Output struct {
+		Message message `json:"message"`
+	} `json:"output"`
+	StopReason string `json:"stopReason"`
+	Usage      usage  `json:"usage"`
+}
+
+type usage struct {
+	InputTokens          int `json:"inputTokens"`
+	OutputTokens         int `json:"outputTokens"`
+	TotalTokens          int `json:"totalTokens"`
+	CacheReadInputTokens int `json:"cacheReadInputTokens"`
+}
+
+func textBlock(s string) contentBlock {
+	return contentBlock{Text: &s}
+}
+
+// convertMessages 将 eino 消息转换为 Converse API 格式, 连续同角色消息合并为一条
+func convertMessages(input []*schema.Message) ([]systemBlock, []message, error) {
+	var system []systemBlock
+	msgs := make([]message, 0, len(input))
+	for _, in := range input {
+		if in == nil {
+			continue
+		}
+		var role string
+		var blocks []contentBlock
+		switch in.Role {
+		case schema.System:
+			if in.Content != "" {
+				system = append(system, systemBlock{Text: in.Content})
+			}
+			continue
+		case schema.User:
+			role = "user"
+			b, err := userBlocks(in)
+			if err != nil {
+				return nil, nil, err
+			}
+			blocks = b
+		case schema.Assistant:
+			role = "assistant"
+			blocks = assistantBlocks(in)
+		case schema.Tool:
+			role = "user"
+			blocks = []contentBlock{{ToolResult: &toolResultBlock{
+				ToolUseID: in.ToolCallID,
+				Content:   []contentBlock{textBlock(in.Content)},
+			}}}
+		default:
+			return nil, nil, fmt.Errorf("unknown role: %s", in.Role)
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+		if n := len(msgs); n > 0 && msgs[n-1].Role == role {
+			msgs[n-1].Content = append(msgs[n-1].Content, blocks...)
+			continue
+		}
+		msgs = append(msgs, message{Role: role, Content: blocks})
+	}
+	return system, msgs, nil
+}
+
+func userBlocks(in *schema.Message) ([]contentBlock, error) {
+	if len(in.UserInputMultiContent) == 0 {
+		if in.Content == "" {
+			return nil, nil
+		}
+		return []contentBlock{textBlock(in.Content)}, nil
+	}
+	blocks := make([]contentBlock, 0, len(in.UserInputMultiContent))
+	for _, part := range in.UserInputMultiContent {
+		switch part.Type {
+		case schema.ChatMessagePartTypeText:
+			blocks = append(blocks, textBlock(part.Text))
+		case schema.ChatMessagePartTypeImageURL:
+			if part.Image == nil {
+				continue
+			}
+			img, err := toImageBlock(&part.Image.MessagePartCommon)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, contentBlock{Image: img})
+		default:
+			return nil, fmt.Errorf("unsupported content type: %s", part.Type)
+		}
+	}
+	return blocks, nil
+}
+
+func toImageBlock(p *schema.MessagePartCommon) (*imageBlock, error) {
+	mimeType := p.MIMEType
+	var data string
+	switch {
+	case p.Base64Data != nil:
+		data = *p.Base64Data
+	case p.URL != nil && strings.HasPrefix(*p.URL, "data:"):
+		meta, d, found := strings.Cut(strings.TrimPrefix(*p.URL, "data:"), ",")
+		if !found {
+			return nil, errors.New("invalid data url")
+		}
+		mimeType = strings.TrimSuffix(meta, ";base64")
+		data = d
+	default:
+		return nil, errors.New("bedrock only supports base64 image input")
+	}
+	format := strings.TrimPrefix(mimeType, "image/")
+	if format == "jpg" {
+		format = "jpeg"
+	}
+	img := &imageBlock{Format: format}
+	img.Source.Bytes = data
+	return img, nil
+}
+
+func assistantBlocks(in *schema.Message) []contentBlock {
+	var blocks []contentBlock
+	if in.ReasoningContent != "" {
+		if sig, ok := in.Extra[ExtraKeyReasoningSignature].(string); ok && sig != "" {
+			blocks = append(blocks, contentBlock{ReasoningContent: &reasoningContent{
+				ReasoningText: &reasoningText{Text: in.ReasoningContent, Signature: sig},
+			}})
+		}
+	}
+	if in.Content != "" {
+		blocks = append(blocks, textBlock(in.Content))
+	}
+	for _, tc := range in.ToolCalls {
+		args := json.RawMessage(tc.Function.Arguments)
+		if len(args) == 0 || !json.Valid(args) {
+			args = json.RawMessage("{}")
+		}
+		blocks = append(blocks, contentBlock{ToolUse: &toolUseBlock{ToolUseID: tc.ID, Name: tc.Function.Name, Input: args}})
+	}
+	return blocks
+}
+
+func convertTools(tools []*schema.ToolInfo, choice *schema.ToolChoice) (*toolConfig, error) {
+	tc := &toolConfig{Tools: make([]toolSpecWrapper, 0, len(tools))}
+	for _, ti := range tools {
+		if ti == nil {
+			continue
+		}
+		js, err := ti.ToJSONSchema()
+		if err != nil {
+			return nil, fmt.Errorf("convert tool %s schema failed: %w", ti.Name, err)
+		}
+		spec := toolSpec{Name: ti.Name, Description: ti.Desc}
+		spec.InputSchema.JSON = js
+		if js == nil {
+			spec.InputSchema.JSON = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		tc.Tools = append(tc.Tools, toolSpecWrapper{ToolSpec: spec})
+	}
+	// Converse 不支持禁止调用工具, 此时保持默认的 auto
+	if choice != nil && *choice == schema.ToolChoiceForced {
+		tc.ToolChoice = map[string]any{"any": map[string]any{}}
+		if len(tc.Tools) == 1 {
+			tc.ToolChoice = map[string]any{"tool": map[string]any{"name": tc.Tools[0].ToolSpec.Name}}
+		}
+	}
+	return tc, nil
+}
+
+func toSchemaMessage(cr *converseResponse) *schema.Message {
+	out := &schema.Message{
+		Role: schema.Assistant,
+		ResponseMeta: &schema.ResponseMeta{
+			FinishReason: cr.StopReason,
+			Usage:        toTokenUsage(cr.Usage),
+		},
+	}
+	var text, reasoning strings.Builder
+	for _, b := range cr.Output.Message.Content {
+		switch {
+		case b.Text != nil:
+			text.WriteString(*b.Text)
+		case b.ReasoningContent != nil && b.ReasoningContent.ReasoningText != nil:
+			reasoning.WriteString(b.ReasoningContent.ReasoningText.Text)
+			if sig := b.ReasoningContent.ReasoningText.Signature; sig != "" {
+				setExtra(out, ExtraKeyReasoningSignature, sig)
+			}
+		case b.ToolUse != nil:
+			if b.ToolUse.Name == responseToolName {
+				text.Write(b.ToolUse.Input)
+				continue
+			}
+			idx := len(out.ToolCalls)
+			args := string(b.ToolUse.Input)
+			if args == "" {
+				args = "{}"
+			}
+			out.ToolCalls = append(out.ToolCalls, schema.ToolCall{
+				Index:    &idx,
+				ID:       b.ToolUse.ToolUseID,
+				Type:     "function",
+				Function: schema.FunctionCall{Name: b.ToolUse.Name, Arguments: args},
+			})
+		}
+	}
+	out.Content = text.String()
+	out.ReasoningContent = reasoning.String()
+	out.ResponseMeta.FinishReason = finishReason(cr.StopReason, len(out.ToolCalls) > 0)
+	return out
+}
+
+// finishReason 仅调用了输出工具时视为正常结束
+func finishReason(stopReason string, hasToolCalls bool) string {
+	if stopReason == "tool_use" && !hasToolCalls {
+		return "end_turn"
+	}
+	return stopReason
+}
+
+func toTokenUsage(u usage) *schema.TokenUsage {
+	total := u.TotalTokens
+	if total == 0 {
+		total = u.InputTokens + u.OutputTokens
+	}
+	return &schema.TokenUsage{
+		PromptTokens:       u.InputTokens,
+		PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: u.CacheReadInputTokens},
+		CompletionTokens:   u.OutputTokens,
+		TotalTokens:        total,
+	}
+}
+
+func setExtra(m *schema.Message, key string, value any) {
+	if m.Extra == nil {
+		m.Extra = map[string]any{}
+	}
+	m.Extra[key] = value
+}
@@ -0,0 +1,231 @@
+package bedrock
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// eventMessage application/vnd.amazon.eventstream 中的一帧
+type eventMessage struct {
+	Headers map[string]string
+	Payload []byte
+}
+
+// readEventMessage 读取一帧 event stream 消息:
+// total_len(4) | headers_len(4) | prelude_crc(4) | headers | payload | message_crc(4)
+func readEventMessage(r io.Reader) (*eventMessage, error) {
+	prelude := make([]byte, 12)
+	if _, err := io.ReadFull(r, prelude); err != nil {
+		return nil, err
+	}
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[0:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, errors.New("event stream prelude checksum mismatch")
+	}
+	if totalLen < 16+headersLen {
+		return nil, errors.New("invalid event stream message length")
+	}
+
+	rest := make([]byte, totalLen-12)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, err
+	}
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(prelude)
+	_, _ = crc.Write(rest[:len(rest)-4])
+	if crc.Sum32() != binary.BigEndian.Uint32(rest[len(rest)-4:]) {
+		return nil, errors.New("event stream message checksum mismatch")
+	}
+
+	headers, err := decodeHeaders(rest[:headersLen])
+	if err != nil {
+		return nil, err
+	}
+	return &eventMessage{Headers: headers, Payload: rest[headersLen : len(rest)-4]}, nil
+}
+
+// decodeHeaders 仅解析字符串类型的头部, 其余类型跳过
+func decodeHeaders(b []byte) (map[string]string, error) {
+	headers := map[string]string{}
+	for len(b) > 0 {
+		nameLen := int(b[0])
+		if len(b) < 1+nameLen+1 {
+			return nil, errors.New("invalid event stream header")
+		}
+		name := string(b[1 : 1+nameLen])
+		typ := b[1+nameLen]
+		b = b[2+nameLen:]
+
+		var size int
+		switch typ {
+		case 0, 1: // bool
+			size = 0
+		case 2: // byte
+			size = 1
+		case 3: // int16
+			size = 2
+		case 4: // int32
+			size = 4
+		case 5, 8: // int64, timestamp
+			size = 8
+		case 9: // uuid
+			size = 16
+		case 6, 7: // bytes, string
+			if len(b) < 2 {
+				return nil, errors.New("invalid event stream header")
+			}
+			size = int(binary.BigEndian.Uint16(b[:2]))
+			b = b[2:]
+			if len(b) < size {
+				return nil, errors.New("invalid event stream header")
+			}
+			if typ == 7 {
+				headers[name] = string(b[:size])
+			}
+		default:
+			return nil, fmt.Errorf("unknown event stream header type: %d", typ)
+		}
+		if len(b) < size {
+			return nil, errors.New("invalid event stream header")
+		}
+		b = b[size:]
+	}
+	return headers, nil
+}
+
+type streamEvent struct {
+	ContentBlockIndex int `json:"contentBlockIndex"`
+	Start             *struct {
+		ToolUse *struct {
+			ToolUseID string `json:"toolUseId"`
+			Name      string `json:"name"`
+		} `json:"toolUse,omitempty"`
+	} `json:"start,omitempty"`
+	Delta *struct {
+		Text    *string `json:"text,omitempty"`
+		ToolUse *struct {
+			Input string `json:"input"`
+		} `json:"toolUse,omitempty"`
+		ReasoningContent *struct {
+			Text      string `json:"text,omitempty"`
+			Signature string `json:"signature,omitempty"`
+		} `json:"reasoningContent,omitempty"`
+	} `json:"delta,omitempty"`
+	StopReason string `json:"stopReason,omitempty"`
+	Usage      *usage `json:"usage,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// readStream 解析 ConverseStream 事件并逐块写入 sw
+func readStream(body io.Reader, sw *schema.StreamWriter[*schema.Message]) {
+	// content block index -> tool call 序号
+	toolIndex := map[int]int{}
+	// 结构化输出工具所在的 content block index
+	outputIndex := map[int]bool{}
+	for {
+		msg, err := readEventMessage(body)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			sw.Send(nil, err)
+			return
+		}
+
+		var ev streamEvent
+		if err := json.Unmarshal(msg.Payload, &ev); err != nil {
+			sw.Send(nil, fmt.Errorf("decode stream event failed: %w", err))
+			return
+		}
+		if msg.Headers[":message-type"] == "exception" || msg.Headers[":message-type"] == "error" {
+			errType := msg.Headers[":exception-type"]
+			if errType == "" {
+				errType = msg.Headers[":error-code"]
+			}
+			sw.Send(nil, fmt.Errorf("%s: %s", errType, ev.Message))
+			return
+		}
+
+		var chunk *schema.Message
+		switch msg.Headers[":event-type"] {
+		case "contentBlockStart":
+			if ev.Start != nil && ev.Start.ToolUse != nil {
+				if ev.Start.ToolUse.Name == responseToolName {
+					outputIndex[ev.ContentBlockIndex] = true
+					break
+				}
+				idx := len(toolIndex)
+				toolIndex[ev.ContentBlockIndex] = idx
+				chunk = &schema.Message{
+					Role: schema.Assistant,
+					ToolCalls: []schema.ToolCall{{
+						Index:    &idx,
+						ID:       ev.Start.ToolUse.ToolUseID,
+						Type:     "function",
+						Function: schema.FunctionCall{Name: ev.Start.ToolUse.Name},
+					}},
+				}
+			}
+		case "contentBlockDelta":
+			chunk = deltaToMessage(&ev, toolIndex, outputIndex)
+		case "messageStop":
+			chunk = &schema.Message{
+				Role:         schema.Assistant,
+				ResponseMeta: &schema.ResponseMeta{FinishReason: finishReason(ev.StopReason, len(toolIndex) > 0)},
+			}
+		case "metadata":
+			if ev.Usage != nil {
+				chunk = &schema.Message{
+					Role:         schema.Assistant,
+					ResponseMeta: &schema.ResponseMeta{Usage: toTokenUsage(*ev.Usage)},
+				}
+			}
+		}
+		if chunk != nil {
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+		}
+	}
+}
+
+func deltaToMessage(ev *streamEvent, toolIndex map[int]int, outputIndex map[int]bool) *schema.Message {
+	if ev.Delta == nil {
+		return nil
+	}
+	d := ev.Delta
+	switch {
+	case d.Text != nil:
+		return &schema.Message{Role: schema.Assistant, Content: *d.Text}
+	case d.ReasoningContent != nil:
+		m := &schema.Message{Role: schema.Assistant, ReasoningContent: d.ReasoningContent.Text}
+		if d.ReasoningContent.Signature != "" {
+			setExtra(m, ExtraKeyReasoningSignature, d.ReasoningContent.Signature)
+		}
+		return m
+	case d.ToolUse != nil:
+		if outputIndex[ev.ContentBlockIndex] {
+			return &schema.Message{Role: schema.Assistant, Content: d.ToolUse.Input}
+		}
+		idx, ok := toolIndex[ev.ContentBlockIndex]
+		if !ok || d.ToolUse.Input == "" {
+			return nil
+		}
+		return &schema.Message{
+			Role: schema.Assistant,
+			ToolCalls: []schema.ToolCall{{
+				Index:    &idx,
+				Function: schema.FunctionCall{Arguments: d.ToolUse.Input},
+			}},
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+package reasoning
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ChatModel 将各提供商不同形式的推理内容统一到 schema.Message.ReasoningContent,
+// reasoning_content 字段与 Gemini thought 由底层模型处理, 这里额外拆分正文开头的 <think>、<thought> 标签
+type ChatModel struct {
+	inner model.BaseChatModel
+}
+
+// NewChatModel 包装任意对话模型, inner 支持 WithTools 时包装后的模型同样支持
+func NewChatModel(inner model.BaseChatModel) (*ChatModel, error) {
+	if inner == nil {
+		return nil, errors.New("inner chat model is nil")
+	}
+	return &ChatModel{inner: inner}, nil
+}
+
+func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	msg, err := cm.inner.Generate(ctx, input, opts...)
+	if err != nil || msg == nil {
+		return msg, err
+	}
+	var p tagParser
+	reasoning, content := p.feed(msg.Content)
+	restReasoning, restContent := p.flush()
+	if r := reasoning + restReasoning; r != "" {
+		if msg.ReasoningContent != "" {
+			r = msg.ReasoningContent + "\n\n" + r
+		}
+		msg.ReasoningContent = r
+	}
+	msg.Content = content + restContent
+	return msg, nil
+}
+
+func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	in, err := cm.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			in.Close()
+			sw.Close()
+		}()
+		var p tagParser
+		for {
+			chunk, err := in.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			if chunk == nil {
+				continue
+			}
+			reasoning, content := p.feed(chunk.Content)
+			chunk.Content = content
+			chunk.ReasoningContent += reasoning
+			if isEmpty(chunk) {
+				continue
+			}
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+		}
+		if reasoning, content := p.flush(); reasoning != "" || content != "" {
+			sw.Send(&schema.Message{Role: schema.Assistant, Content: content, ReasoningContent: reasoning}, nil)
+		}
+	}()
+	return sr, nil
+}
+
+// WithTools 底层模型不支持工具调用时返回错误
+func (cm *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	tcm, ok := cm.inner.(model.ToolCallingChatModel)
+	if !ok {
+		return nil, errors.New("inner chat model does not support tool calling")
+	}
+	inner, err := tcm.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatModel{inner: inner}, nil
+}
+
+func (cm *ChatModel) GetType() string {
+	if typ, ok := components.GetType(cm.inner); ok {
+		return typ
+	}
+	return "Reasoning"
+}
+
+// IsCallbacksEnabled 回调由底层模型触发, 避免重复
+func (cm *ChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(cm.inner)
+}
+
+// isEmpty 推理标签被缓存时对应分块可能不再包含任何内容
+func isEmpty(m *schema.Message) bool {
+	return m.Content == "" && m.ReasoningContent == "" && len(m.ToolCalls) == 0 &&
+		len(m.AssistantGenMultiContent) == 0 && m.ResponseMeta == nil && len(m.Extra) == 0
+}
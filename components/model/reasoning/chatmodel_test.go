@@ -0,0 +1,117 @@
+package reasoning
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// fakeChatModel 按固定结果响应的底层模型
+type fakeChatModel struct {
+	reply  *schema.Message
+	chunks []*schema.Message
+}
+
+func (f *fakeChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return f.reply, nil
+}
+
+func (f *fakeChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return schema.StreamReaderFromArray(f.chunks), nil
+}
+
+func collect(t *testing.T, cm *ChatModel) *schema.Message {
+	t.Helper()
+	sr, err := cm.Stream(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	defer sr.Close()
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	msg, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		t.Fatalf("ConcatMessages failed: %v", err)
+	}
+	return msg
+}
+
+func TestChatModel_Generate(t *testing.T) {
+	cm, _ := NewChatModel(&fakeChatModel{
+		reply: &schema.Message{Role: schema.Assistant, Content: "<think>\nUser greets me.\n</think>\n\nHello!"},
+	})
+	msg, err := cm.Generate(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if msg.ReasoningContent != "User greets me." || msg.Content != "Hello!" {
+		t.Fatalf("unexpected message: %q %q", msg.ReasoningContent, msg.Content)
+	}
+}
+
+func TestChatModel_Stream(t *testing.T) {
+	cases := []struct {
+		name      string
+		chunks    []*schema.Message
+		reasoning string
+		content   string
+	}{
+		{
+			name: "split tags",
+			chunks: []*schema.Message{
+				{Role: schema.Assistant, Content: "\n<thi"},
+				{Content: "nk>\nUser gre"},
+				{Content: "ets me.\n</th"},
+				{Content: "ink>"},
+				{Content: "\n\nHel"},
+				{Content: "lo! <think> stays"},
+			},
+			reasoning: "User greets me.",
+			content:   "Hello! <think> stays",
+		},
+		{
+			name: "reasoning_content",
+			chunks: []*schema.Message{
+				{Role: schema.Assistant, ReasoningContent: "Let me "},
+				{ReasoningContent: "think."},
+				{Content: "Answer"},
+			},
+			reasoning: "Let me think.",
+			content:   "Answer",
+		},
+		{
+			name: "unclosed thought",
+			chunks: []*schema.Message{
+				{Role: schema.Assistant, Content: "<thought>cut off"},
+				{Content: " mid-thought\n"},
+			},
+			reasoning: "cut off mid-thought",
+		},
+	}
+	for _, tc := range cases {
+		cm, _ := NewChatModel(&fakeChatModel{chunks: tc.chunks})
+		if msg := collect(t, cm); msg.ReasoningContent != tc.reasoning || msg.Content != tc.content {
+			t.Errorf("%s: unexpected message: %q %q", tc.name, msg.ReasoningContent, msg.Content)
+		}
+	}
+}
+
+func TestChatModel_WithToolsUnsupported(t *testing.T) {
+	cm, _ := NewChatModel(&fakeChatModel{})
+	if _, err := cm.WithTools([]*schema.ToolInfo{{Name: "f"}}); err == nil {
+		t.Fatal("expected error for inner model without tool calling")
+	}
+}
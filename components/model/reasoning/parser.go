@@ -0,0 +1,118 @@
+package reasoning
+
+import "strings"
+
+// thinkTags 内联推理内容的标签, R1 类模型使用 <think>, Gemini OpenAI 兼容接口使用 <thought>
+var thinkTags = []struct {
+	open  string
+	close string
+}{
+	{open: "<think>", close: "</think>"},
+	{open: "<thought>", close: "</thought>"},
+}
+
+type parserState int
+
+const (
+	// 等待首个非空白字符, 判断是否以推理标签开头
+	stateStart parserState = iota
+	// 推理标签内
+	stateThink
+	// 推理标签结束后, 去掉紧随的换行
+	stateAfterThink
+	// 正文, 不再解析标签
+	stateContent
+)
+
+// tagParser 从正文中拆分出开头的推理标签, 标签可能被拆分到多个流式分块中.
+// 只识别正文开头的标签, 正文中出现的标签原样保留
+type tagParser struct {
+	state    parserState
+	closeTag string
+	pending  string
+	// 推理内容已开始输出, 此前去掉开始标签后的换行
+	started bool
+}
+
+// feed 写入一段正文, 返回可以确定的推理内容与正文, 无法确定的部分留到下一次
+func (p *tagParser) feed(s string) (reasoning, content string) {
+	p.pending += s
+	for {
+		switch p.state {
+		case stateStart:
+			trimmed := strings.TrimLeft(p.pending, " \t\r\n")
+			if trimmed == "" {
+				return
+			}
+			next := stateContent
+			for _, t := range thinkTags {
+				if strings.HasPrefix(trimmed, t.open) {
+					next = stateThink
+					p.closeTag = t.close
+					p.pending = trimmed[len(t.open):]
+					break
+				}
+				if strings.HasPrefix(t.open, trimmed) {
+					// 标签不完整, 等待后续分块
+					return
+				}
+			}
+			p.state = next
+		case stateThink:
+			if !p.started {
+				p.pending = strings.TrimLeft(p.pending, "\r\n")
+				if p.pending == "" {
+					return
+				}
+				p.started = true
+			}
+			if i := strings.Index(p.pending, p.closeTag); i >= 0 {
+				reasoning += strings.TrimRight(p.pending[:i], " \t\r\n")
+				p.pending = p.pending[i+len(p.closeTag):]
+				p.state = stateAfterThink
+				continue
+			}
+			// 保留可能是结束标签前缀的部分以及末尾空白
+			keep := len(p.pending) - partialSuffix(p.pending, p.closeTag)
+			keep = len(strings.TrimRight(p.pending[:keep], " \t\r\n"))
+			reasoning += p.pending[:keep]
+			p.pending = p.pending[keep:]
+			return
+		case stateAfterThink:
+			p.pending = strings.TrimLeft(p.pending, "\r\n")
+			if p.pending == "" {
+				return
+			}
+			p.state = stateContent
+		case stateContent:
+			content += p.pending
+			p.pending = ""
+			return
+		}
+	}
+}
+
+// flush 输出剩余内容, 未闭合的推理标签按推理内容处理
+func (p *tagParser) flush() (reasoning, content string) {
+	rest := p.pending
+	p.pending = ""
+	switch p.state {
+	case stateThink:
+		return strings.TrimRight(rest, " \t\r\n"), ""
+	case stateStart:
+		if strings.TrimSpace(rest) == "" {
+			return "", ""
+		}
+	}
+	return "", rest
+}
+
+// partialSuffix 返回 s 末尾与 tag 前缀相同的最大长度
+func partialSuffix(s, tag string) int {
+	for n := min(len(s), len(tag)-1); n > 0; n-- {
+		if strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package reasoning
+
+import "testing"
+
+func TestTagParser(t *testing.T) {
+	cases := []struct {
+		name      string
+		input     string
+		reasoning string
+		content   string
+	}{
+		{"think", "<think>\nUser greets me.\n</think>\n\nHello!", "User greets me.", "Hello!"},
+		{"thought", "<thought>plan</thought>answer", "plan", "answer"},
+		{"leading whitespace", " \n\t<think>r</think>c", "r", "c"},
+		{"whitespace only", " \n ", "", ""},
+		{"no tag", "Hello <think>x</think>", "", "Hello <think>x</think>"},
+		{"tag in content", "<think>r</think>\nsee <think> here", "r", "see <think> here"},
+		{"unclosed think", "<think>cut off mid-thought\n", "cut off mid-thought", ""},
+		{"unclosed thought", "<thought>still going", "still going", ""},
+		{"partial open tag", "<thi", "", "<thi"},
+		{"other tag", "<b>bold</b>", "", "<b>bold</b>"},
+		{"empty reasoning", "<think></think>answer", "", "answer"},
+		{"close tag prefix in reasoning", "<think>a </th b</think>c", "a </th b", "c"},
+	}
+	for _, tc := range cases {
+		// 在每个字节处拆分为两个分块, 并逐字节输入
+		splits := [][]string{{tc.input}}
+		for i := 1; i < len(tc.input); i++ {
+			splits = append(splits, []string{tc.input[:i], tc.input[i:]})
+		}
+		bytewise := make([]string, len(tc.input))
+		for i := range tc.input {
+			bytewise[i] = tc.input[i : i+1]
+		}
+		splits = append(splits, bytewise)
+
+		for _, chunks := range splits {
+			var p tagParser
+			var reasoning, content string
+			for _, c := range chunks {
+				r, s := p.feed(c)
+				reasoning += r
+				content += s
+			}
+			r, s := p.flush()
+			reasoning += r
+			content += s
+			if reasoning != tc.reasoning || content != tc.content {
+				t.Errorf("%s %q: got reasoning=%q content=%q", tc.name, chunks, reasoning, content)
+			}
+		}
+	}
+}
+
+func TestTagParser_WhitespaceFirstChunk(t *testing.T) {
+	var p tagParser
+	// 首个分块只有空白时无法判断是否以标签开头, 不能提前输出
+	if r, c := p.feed("\n\n"); r != "" || c != "" {
+		t.Fatalf("whitespace chunk emitted: %q %q", r, c)
+	}
+	if r, c := p.feed("<think>x"); r != "x" || c != "" {
+		t.Fatalf("unexpected reasoning chunk: %q %q", r, c)
+	}
+	if r, c := p.feed("</think>\nok"); r != "" || c != "ok" {
+		t.Fatalf("unexpected content chunk: %q %q", r, c)
+	}
+}
@@ -0,0 +1,271 @@
+package responses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultBaseURL = "https://api.openai.com/v1"
+	// Azure OpenAI v1 接口不需要指定日期版本
+	azureV1APIVersion = "v1"
+)
+
+type ChatModelConfig struct {
+	APIKey string
+	Model  string
+	// 默认 https://api.openai.com/v1, 以 # 结尾时原样作为请求地址
+	BaseURL    string
+	HTTPClient *http.Client
+
+	// Azure OpenAI 使用 api-key 请求头, BaseURL 为资源地址, Model 为部署名称
+	ByAzure bool
+	// 仅 Azure 使用, 为空或 v1 时调用 /openai/v1/responses, 否则调用 /openai/responses?api-version=
+	APIVersion string
+
+	MaxOutputTokens *int
+	Temperature     *float32
+	TopP            *float32
+	// 推理强度: minimal、low、medium、high
+	ReasoningEffort string
+	// 推理摘要: auto、concise、detailed, 结果写入 ReasoningContent
+	ReasoningSummary string
+	// 内置工具原样透传, 例如 {"type": "web_search"}、{"type": "code_interpreter", "container": {"type": "auto"}}
+	BuiltinTools []map[string]any
+	// 续接上一次响应, 仅发送新增消息即可, 也可通过 WithPreviousResponseID 按请求指定
+	PreviousResponseID string
+	// 是否在服务端保存响应, 使用 previous_response_id 续接时需要保存
+	Store *bool
+	// 结构化输出格式, 对应请求中的 text.format
+	TextFormat *TextFormat
+}
+
+type ChatModel struct {
+	cfg        *ChatModelConfig
+	httpClient *http.Client
+	endpoint   string
+	tools      []*schema.ToolInfo
+}
+
+type implOptions struct {
+	PreviousResponseID *string
+}
+
+// WithPreviousResponseID 指定本次请求续接的响应 ID, 通常取自上一条回复的 Extra[ExtraKeyResponseID]
+func WithPreviousResponseID(id string) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *implOptions) {
+		o.PreviousResponseID = &id
+	})
+}
+
+func NewChatModel(ctx context.Context, cfg *ChatModelConfig) (*ChatModel, error) {
+	if cfg == nil || cfg.Model == "" {
+		return nil, errors.New("invalid chat model config")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	endpoint, err := buildEndpoint(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatModel{
+		cfg:        cfg,
+		httpClient: httpClient,
+		endpoint:   endpoint,
+	}, nil
+}
+
+func buildEndpoint(cfg *ChatModelConfig) (string, error) {
+	if strings.HasSuffix(cfg.BaseURL, "#") {
+		return strings.TrimSuffix(cfg.BaseURL, "#"), nil
+	}
+	if !cfg.ByAzure {
+		u := strings.TrimSuffix(cfg.BaseURL, "/")
+		if u == "" {
+			u = defaultBaseURL
+		}
+		return strings.TrimSuffix(u, "/responses") + "/responses", nil
+	}
+
+	u, err := url.Parse(cfg.BaseURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid azure endpoint: %s", cfg.BaseURL)
+	}
+	// 去掉用户填写的 /openai 及之后的路径
+	if i := strings.Index(u.Path, "/openai"); i >= 0 {
+		u.Path = u.Path[:i]
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawQuery = ""
+	if cfg.APIVersion == "" || cfg.APIVersion == azureV1APIVersion || cfg.APIVersion == "preview" {
+		u.Path += "/openai/v1/responses"
+		if cfg.APIVersion == "preview" {
+			u.RawQuery = "api-version=preview"
+		}
+		return u.String(), nil
+	}
+	u.Path += "/openai/responses"
+	u.RawQuery = url.Values{"api-version": {cfg.APIVersion}}.Encode()
+	return u.String(), nil
+}
+
+func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	reqBody, err := cm.buildRequest(input, false, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cm.do(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+	if r.Error != nil && r.Error.Message != "" {
+		return nil, fmt.Errorf("%s: %s", r.Error.Code, r.Error.Message)
+	}
+	return toSchemaMessage(&r), nil
+}
+
+func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	reqBody, err := cm.buildRequest(input, true, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cm.do(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			_ = resp.Body.Close()
+			sw.Close()
+		}()
+		readStream(resp.Body, sw)
+	}()
+	return sr, nil
+}
+
+// WithTools 返回绑定了工具的新实例, 不修改当前实例
+func (cm *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	if len(tools) == 0 {
+		return nil, errors.New("no tools to bind")
+	}
+	ncm := *cm
+	ncm.tools = tools
+	return &ncm, nil
+}
+
+func (cm *ChatModel) GetType() string {
+	return "OpenAIResponses"
+}
+
+func (cm *ChatModel) do(ctx context.Context, body *request) (*http.Response, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, cm.endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if cm.cfg.ByAzure {
+		httpReq.Header.Set("api-key", cm.cfg.APIKey)
+	} else {
+		httpReq.Header.Set("Authorization", "Bearer "+cm.cfg.APIKey)
+	}
+	if body.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	resp, err := cm.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		b, _ := io.ReadAll(resp.Body)
+		var er errorResponse
+		if json.Unmarshal(b, &er) == nil && er.Error.Message != "" {
+			return nil, fmt.Errorf("%s: %s", resp.Status, er.Error.Message)
+		}
+		return nil, errors.New(resp.Status)
+	}
+	return resp, nil
+}
+
+func (cm *ChatModel) buildRequest(input []*schema.Message, stream bool, opts ...model.Option) (*request, error) {
+	modelName := cm.cfg.Model
+	options := model.GetCommonOptions(&model.Options{
+		Temperature: cm.cfg.Temperature,
+		MaxTokens:   cm.cfg.MaxOutputTokens,
+		Model:       &modelName,
+		TopP:        cm.cfg.TopP,
+		Tools:       cm.tools,
+	}, opts...)
+	specific := model.GetImplSpecificOptions(&implOptions{
+		PreviousResponseID: &cm.cfg.PreviousResponseID,
+	}, opts...)
+
+	instructions, items, err := convertMessages(input)
+	if err != nil {
+		return nil, err
+	}
+	req := &request{
+		Model:           *options.Model,
+		Input:           items,
+		Instructions:    instructions,
+		MaxOutputTokens: options.MaxTokens,
+		Temperature:     options.Temperature,
+		TopP:            options.TopP,
+		Store:           cm.cfg.Store,
+		Stream:          stream,
+	}
+	if cm.cfg.TextFormat != nil {
+		req.Text = &textConfig{Format: cm.cfg.TextFormat}
+	}
+	if specific.PreviousResponseID != nil {
+		req.PreviousResponseID = *specific.PreviousResponseID
+	}
+	if cm.cfg.ReasoningEffort != "" || cm.cfg.ReasoningSummary != "" {
+		req.Reasoning = &reasoning{Effort: cm.cfg.ReasoningEffort, Summary: cm.cfg.ReasoningSummary}
+	}
+
+	tools, err := convertTools(options.Tools)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range cm.cfg.BuiltinTools {
+		tools = append(tools, t)
+	}
+	req.Tools = tools
+	if options.ToolChoice != nil {
+		switch *options.ToolChoice {
+		case schema.ToolChoiceForbidden:
+			req.ToolChoice = "none"
+		case schema.ToolChoiceForced:
+			req.ToolChoice = "required"
+		default:
+			req.ToolChoice = "auto"
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,93 @@
+package responses
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+func TestBuildEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		cfg  ChatModelConfig
+		want string
+	}{
+		{ChatModelConfig{}, "https://api.openai.com/v1/responses"},
+		{ChatModelConfig{BaseURL: "https://relay.example.com/v1/"}, "https://relay.example.com/v1/responses"},
+		{ChatModelConfig{BaseURL: "https://relay.example.com/custom#"}, "https://relay.example.com/custom"},
+		{ChatModelConfig{BaseURL: "https://res.openai.azure.com/openai", ByAzure: true}, "https://res.openai.azure.com/openai/v1/responses"},
+		{ChatModelConfig{BaseURL: "https://res.openai.azure.com", ByAzure: true, APIVersion: "preview"}, "https://res.openai.azure.com/openai/v1/responses?api-version=preview"},
+		{ChatModelConfig{BaseURL: "https://res.openai.azure.com/openai/v1", ByAzure: true, APIVersion: "2025-04-01-preview"}, "https://res.openai.azure.com/openai/responses?api-version=2025-04-01-preview"},
+	} {
+		got, err := buildEndpoint(&tc.cfg)
+		if err != nil || got != tc.want {
+			t.Errorf("%+v: got %q %v, want %q", tc.cfg, got, err, tc.want)
+		}
+	}
+}
+
+func TestChatModel_Stream(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"POST /v1/responses": func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"stream":true`) || !strings.Contains(string(body), `"type":"function"`) ||
+				!strings.Contains(string(body), `"previous_response_id":"resp_8"`) {
+				t.Errorf("unexpected request body: %s", body)
+			}
+			standin.WriteSSE(w,
+				`{"type":"response.created","response":{"id":"resp_9","status":"in_progress"}}`,
+				`{"type":"response.reasoning_summary_text.delta","output_index":0,"summary_index":0,"delta":"Need "}`,
+				`{"type":"response.reasoning_summary_text.delta","output_index":0,"summary_index":0,"delta":"weather"}`,
+				`{"type":"response.output_text.delta","output_index":1,"delta":"Checking"}`,
+				`{"type":"response.output_item.added","output_index":2,"item":{"type":"function_call","call_id":"call_1","name":"get_weather","arguments":""}}`,
+				`{"type":"response.function_call_arguments.delta","output_index":2,"delta":"{\"city\":"}`,
+				`{"type":"response.function_call_arguments.delta","output_index":2,"delta":"\"Paris\"}"}`,
+				`{"type":"response.completed","response":{"id":"resp_9","status":"completed","usage":{"input_tokens":8,"output_tokens":6,"total_tokens":14}}}`,
+			)
+		},
+	})
+
+	ctx := context.Background()
+	cm, err := NewChatModel(ctx, &ChatModelConfig{Model: "gpt-5", BaseURL: ts.URL + "/v1", APIKey: "sk-test"})
+	if err != nil {
+		t.Fatalf("NewChatModel failed: %v", err)
+	}
+	tcm, err := cm.WithTools([]*schema.ToolInfo{{Name: "get_weather", Desc: "weather"}})
+	if err != nil {
+		t.Fatalf("WithTools failed: %v", err)
+	}
+	sr, err := tcm.Stream(ctx, []*schema.Message{schema.UserMessage("weather in Paris?")}, WithPreviousResponseID("resp_8"))
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	defer sr.Close()
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	msg, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		t.Fatalf("ConcatMessages failed: %v", err)
+	}
+	if msg.Content != "Checking" || msg.ReasoningContent != "Need weather" || len(msg.ToolCalls) != 1 ||
+		msg.ToolCalls[0].ID != "call_1" || msg.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if msg.ResponseMeta.FinishReason != "tool_calls" || msg.ResponseMeta.Usage.TotalTokens != 14 ||
+		msg.Extra[ExtraKeyResponseID] != "resp_9" {
+		t.Fatalf("unexpected response meta: %+v %v", msg.ResponseMeta, msg.Extra)
+	}
+}
@@ -0,0 +1,283 @@
+package responses
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ExtraKeyResponseID 响应 ID 在 schema.Message.Extra 中的键,
+// 可作为下一轮请求的 previous_response_id
+const ExtraKeyResponseID = "openai_response_id"
+
+type request struct {
+	Model              string      `json:"model"`
+	Input              []item      `json:"input"`
+	Instructions       string      `json:"instructions,omitempty"`
+	MaxOutputTokens    *int        `json:"max_output_tokens,omitempty"`
+	Temperature        *float32    `json:"temperature,omitempty"`
+	TopP               *float32    `json:"top_p,omitempty"`
+	Reasoning          *reasoning  `json:"reasoning,omitempty"`
+	Tools              []any       `json:"tools,omitempty"`
+	ToolChoice         string      `json:"tool_choice,omitempty"`
+	PreviousResponseID string      `json:"previous_response_id,omitempty"`
+	Store              *bool       `json:"store,omitempty"`
+	Text               *textConfig `json:"text,omitempty"`
+	Stream             bool        `json:"stream,omitempty"`
+}
+
+type textConfig struct {
+	Format *TextFormat `json:"format"`
+}
+
+// TextFormat 结构化输出格式, Type 为 json_object 或 json_schema,
+// json_schema 时需要设置 Name 与 Schema
+type TextFormat struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Schema      any    `json:"schema,omitempty"`
+	Strict      bool   `json:"strict,omitempty"`
+}
+
+type reasoning struct {
+	Effort  string `json:"effort,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type functionTool struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters"`
+}
+
+// item 同时用于输入和输出, 按 Type 区分 message、function_call、function_call_output、reasoning
+type item struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+	// message
+	Role    string        `json:"role,omitempty"`
+	Content []contentPart `json:"content,omitempty"`
+	// function_call
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	// function_call_output
+	Output string `json:"output,omitempty"`
+	// reasoning
+	Summary []contentPart `json:"summary,omitempty"`
+}
+
+type contentPart struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	Refusal  string `json:"refusal,omitempty"`
+}
+
+type response struct {
+	ID                string `json:"id"`
+	Status            string `json:"status"`
+	Model             string `json:"model"`
+	Output            []item `json:"output"`
+	Usage             *usage `json:"usage,omitempty"`
+	IncompleteDetails *struct {
+		Reason string `json:"reason"`
+	} `json:"incomplete_details,omitempty"`
+	Error *apiError `json:"error,omitempty"`
+}
+
+type usage struct {
+	InputTokens        int `json:"input_tokens"`
+	InputTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"input_tokens_details"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	Error apiError `json:"error"`
+}
+
+// convertMessages 将 eino 消息转换为 Responses API 的 input,
+// system 消息合并为 instructions
+func convertMessages(input []*schema.Message) (string, []item, error) {
+	var systems []string
+	items := make([]item, 0, len(input))
+	for _, in := range input {
+		if in == nil {
+			continue
+		}
+		switch in.Role {
+		case schema.System:
+			systems = append(systems, in.Content)
+		case schema.User:
+			parts, err := userParts(in)
+			if err != nil {
+				return "", nil, err
+			}
+			if len(parts) > 0 {
+				items = append(items, item{Type: "message", Role: "user", Content: parts})
+			}
+		case schema.Assistant:
+			if in.Content != "" {
+				items = append(items, item{
+					Type:    "message",
+					Role:    "assistant",
+					Content: []contentPart{{Type: "output_text", Text: in.Content}},
+				})
+			}
+			for _, tc := range in.ToolCalls {
+				args := tc.Function.Arguments
+				if args == "" {
+					args = "{}"
+				}
+				items = append(items, item{Type: "function_call", CallID: tc.ID, Name: tc.Function.Name, Arguments: args})
+			}
+		case schema.Tool:
+			items = append(items, item{Type: "function_call_output", CallID: in.ToolCallID, Output: in.Content})
+		default:
+			return "", nil, fmt.Errorf("unknown role: %s", in.Role)
+		}
+	}
+	return strings.Join(systems, "\n"), items, nil
+}
+
+func userParts(in *schema.Message) ([]contentPart, error) {
+	if len(in.UserInputMultiContent) == 0 {
+		if in.Content == "" {
+			return nil, nil
+		}
+		return []contentPart{{Type: "input_text", Text: in.Content}}, nil
+	}
+	parts := make([]contentPart, 0, len(in.UserInputMultiContent))
+	for _, part := range in.UserInputMultiContent {
+		switch part.Type {
+		case schema.ChatMessagePartTypeText:
+			parts = append(parts, contentPart{Type: "input_text", Text: part.Text})
+		case schema.ChatMessagePartTypeImageURL:
+			if part.Image == nil {
+				continue
+			}
+			u, err := toImageURL(&part.Image.MessagePartCommon)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, contentPart{Type: "input_image", ImageURL: u})
+		default:
+			return nil, fmt.Errorf("unsupported content type: %s", part.Type)
+		}
+	}
+	return parts, nil
+}
+
+func toImageURL(p *schema.MessagePartCommon) (string, error) {
+	if p.Base64Data != nil {
+		return "data:" + p.MIMEType + ";base64," + *p.Base64Data, nil
+	}
+	if p.URL == nil {
+		return "", errors.New("image url is empty")
+	}
+	return *p.URL, nil
+}
+
+func convertTools(tools []*schema.ToolInfo) ([]any, error) {
+	out := make([]any, 0, len(tools))
+	for _, ti := range tools {
+		if ti == nil {
+			continue
+		}
+		js, err := ti.ToJSONSchema()
+		if err != nil {
+			return nil, fmt.Errorf("convert tool %s schema failed: %w", ti.Name, err)
+		}
+		var params any = js
+		if js == nil {
+			params = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		out = append(out, functionTool{Type: "function", Name: ti.Name, Description: ti.Desc, Parameters: params})
+	}
+	return out, nil
+}
+
+func toSchemaMessage(r *response) *schema.Message {
+	out := &schema.Message{
+		Role:         schema.Assistant,
+		ResponseMeta: &schema.ResponseMeta{Usage: toTokenUsage(r.Usage)},
+	}
+	var text, summary strings.Builder
+	for _, it := range r.Output {
+		switch it.Type {
+		case "message":
+			for _, c := range it.Content {
+				switch c.Type {
+				case "output_text":
+					text.WriteString(c.Text)
+				case "refusal":
+					text.WriteString(c.Refusal)
+				}
+			}
+		case "reasoning":
+			for _, s := range it.Summary {
+				if summary.Len() > 0 {
+					summary.WriteString("\n\n")
+				}
+				summary.WriteString(s.Text)
+			}
+		case "function_call":
+			idx := len(out.ToolCalls)
+			out.ToolCalls = append(out.ToolCalls, schema.ToolCall{
+				Index:    &idx,
+				ID:       it.CallID,
+				Type:     "function",
+				Function: schema.FunctionCall{Name: it.Name, Arguments: it.Arguments},
+			})
+		}
+	}
+	out.Content = text.String()
+	out.ReasoningContent = summary.String()
+	out.ResponseMeta.FinishReason = finishReason(r, len(out.ToolCalls) > 0)
+	if r.ID != "" {
+		setExtra(out, ExtraKeyResponseID, r.ID)
+	}
+	return out
+}
+
+// finishReason 对齐 Chat Completions 的取值, 未完成时返回 incomplete_details.reason
+func finishReason(r *response, hasToolCalls bool) string {
+	if r.IncompleteDetails != nil && r.IncompleteDetails.Reason != "" {
+		return r.IncompleteDetails.Reason
+	}
+	if hasToolCalls {
+		return "tool_calls"
+	}
+	return "stop"
+}
+
+func toTokenUsage(u *usage) *schema.TokenUsage {
+	if u == nil {
+		return nil
+	}
+	return &schema.TokenUsage{
+		PromptTokens:       u.InputTokens,
+		PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: u.InputTokensDetails.CachedTokens},
+		CompletionTokens:   u.OutputTokens,
+		TotalTokens:        u.TotalTokens,
+	}
+}
+
+func setExtra(m *schema.Message, key string, value any) {
+	if m.Extra == nil {
+		m.Extra = map[string]any{}
+	}
+	m.Extra[key] = value
+}
@@ -0,0 +1,130 @@
+package responses
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type streamEvent struct {
+	Type         string    `json:"type"`
+	OutputIndex  int       `json:"output_index"`
+	SummaryIndex int       `json:"summary_index"`
+	Delta        string    `json:"delta,omitempty"`
+	Item         *item     `json:"item,omitempty"`
+	Response     *response `json:"response,omitempty"`
+	// error 事件
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// readStream 解析 SSE 事件并逐块写入 sw
+func readStream(body io.Reader, sw *schema.StreamWriter[*schema.Message]) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	// output index -> tool call 序号
+	toolIndex := map[int]int{}
+	// 多段推理摘要之间补充空行, 与非流式结果保持一致
+	lastSummary := -1
+
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "" || data == "[DONE]" {
+			continue
+		}
+		var ev streamEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			sw.Send(nil, fmt.Errorf("decode stream event failed: %w", err))
+			return
+		}
+
+		var chunk *schema.Message
+		switch ev.Type {
+		case "response.output_item.added":
+			if ev.Item != nil && ev.Item.Type == "function_call" {
+				idx := len(toolIndex)
+				toolIndex[ev.OutputIndex] = idx
+				chunk = &schema.Message{
+					Role: schema.Assistant,
+					ToolCalls: []schema.ToolCall{{
+						Index:    &idx,
+						ID:       ev.Item.CallID,
+						Type:     "function",
+						Function: schema.FunctionCall{Name: ev.Item.Name, Arguments: ev.Item.Arguments},
+					}},
+				}
+			}
+		case "response.output_text.delta", "response.refusal.delta":
+			if ev.Delta != "" {
+				chunk = &schema.Message{Role: schema.Assistant, Content: ev.Delta}
+			}
+		case "response.reasoning_summary_text.delta":
+			if ev.Delta == "" {
+				break
+			}
+			delta := ev.Delta
+			key := ev.OutputIndex<<16 | ev.SummaryIndex
+			if lastSummary >= 0 && lastSummary != key {
+				delta = "\n\n" + delta
+			}
+			lastSummary = key
+			chunk = &schema.Message{Role: schema.Assistant, ReasoningContent: delta}
+		case "response.function_call_arguments.delta":
+			idx, ok := toolIndex[ev.OutputIndex]
+			if !ok || ev.Delta == "" {
+				break
+			}
+			chunk = &schema.Message{
+				Role: schema.Assistant,
+				ToolCalls: []schema.ToolCall{{
+					Index:    &idx,
+					Function: schema.FunctionCall{Arguments: ev.Delta},
+				}},
+			}
+		case "response.completed", "response.incomplete":
+			if ev.Response == nil {
+				return
+			}
+			chunk = &schema.Message{
+				Role: schema.Assistant,
+				ResponseMeta: &schema.ResponseMeta{
+					FinishReason: finishReason(ev.Response, len(toolIndex) > 0),
+					Usage:        toTokenUsage(ev.Response.Usage),
+				},
+			}
+			if ev.Response.ID != "" {
+				setExtra(chunk, ExtraKeyResponseID, ev.Response.ID)
+			}
+			sw.Send(chunk, nil)
+			return
+		case "response.failed":
+			msg := "response failed"
+			if ev.Response != nil && ev.Response.Error != nil {
+				msg = ev.Response.Error.Code + ": " + ev.Response.Error.Message
+			}
+			sw.Send(nil, errors.New(msg))
+			return
+		case "error":
+			sw.Send(nil, errors.New(ev.Code+": "+ev.Message))
+			return
+		}
+		if chunk != nil {
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		sw.Send(nil, err)
+	}
+}
@@ -0,0 +1,124 @@
+package toolcall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ChatModel 统一各提供商的工具调用行为:
+// 无参数的工具补全为空对象 Schema, 工具调用补全 Index、ID、Type 与参数,
+// 流式输出中同一调用的参数增量使用相同的 Index, 并行调用按出现顺序从 0 编号
+type ChatModel struct {
+	inner model.ToolCallingChatModel
+}
+
+// NewChatModel 包装支持工具调用的对话模型
+func NewChatModel(inner model.ToolCallingChatModel) (*ChatModel, error) {
+	if inner == nil {
+		return nil, errors.New("inner chat model is nil")
+	}
+	return &ChatModel{inner: inner}, nil
+}
+
+func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	msg, err := cm.inner.Generate(ctx, input, opts...)
+	if err != nil || msg == nil {
+		return msg, err
+	}
+	normalizeToolCalls(msg.ToolCalls)
+	return msg, nil
+}
+
+func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	in, err := cm.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			in.Close()
+			sw.Close()
+		}()
+		var t tracker
+		for {
+			chunk, err := in.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			if chunk == nil {
+				continue
+			}
+			t.feed(chunk.ToolCalls)
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+		}
+		if calls := t.flush(); len(calls) > 0 {
+			sw.Send(&schema.Message{Role: schema.Assistant, ToolCalls: calls}, nil)
+		}
+	}()
+	return sr, nil
+}
+
+// WithTools 工具名不能为空或重复, 未设置参数的工具按无参数处理
+func (cm *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	normalized, err := normalizeTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := cm.inner.WithTools(normalized)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatModel{inner: inner}, nil
+}
+
+func (cm *ChatModel) GetType() string {
+	if typ, ok := components.GetType(cm.inner); ok {
+		return typ
+	}
+	return "ToolCalling"
+}
+
+// IsCallbacksEnabled 回调由底层模型触发, 避免重复
+func (cm *ChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(cm.inner)
+}
+
+func normalizeTools(tools []*schema.ToolInfo) ([]*schema.ToolInfo, error) {
+	out := make([]*schema.ToolInfo, 0, len(tools))
+	seen := make(map[string]bool, len(tools))
+	for _, ti := range tools {
+		if ti == nil {
+			continue
+		}
+		if ti.Name == "" {
+			return nil, errors.New("tool name is empty")
+		}
+		if seen[ti.Name] {
+			return nil, fmt.Errorf("duplicate tool name: %s", ti.Name)
+		}
+		seen[ti.Name] = true
+		if ti.ParamsOneOf == nil {
+			cp := *ti
+			cp.ParamsOneOf = schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{})
+			ti = &cp
+		}
+		out = append(out, ti)
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no tools to bind")
+	}
+	return out, nil
+}
@@ -0,0 +1,146 @@
+package toolcall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// fakeChatModel 按固定结果响应, 并记录绑定的工具
+type fakeChatModel struct {
+	reply  *schema.Message
+	chunks []*schema.Message
+	tools  []*schema.ToolInfo
+}
+
+func (f *fakeChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return f.reply, nil
+}
+
+func (f *fakeChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return schema.StreamReaderFromArray(f.chunks), nil
+}
+
+func (f *fakeChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	f.tools = tools
+	return f, nil
+}
+
+func intPtr(i int) *int { return &i }
+
+func call(index *int, id, name, args string) schema.ToolCall {
+	return schema.ToolCall{Index: index, ID: id, Function: schema.FunctionCall{Name: name, Arguments: args}}
+}
+
+func streamToolCalls(t *testing.T, chunks ...schema.ToolCall) []schema.ToolCall {
+	t.Helper()
+	in := make([]*schema.Message, len(chunks))
+	for i, tc := range chunks {
+		in[i] = &schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{tc}}
+	}
+	cm, _ := NewChatModel(&fakeChatModel{chunks: in})
+	sr, err := cm.Stream(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	defer sr.Close()
+	var out []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		out = append(out, chunk)
+	}
+	msg, err := schema.ConcatMessages(out)
+	if err != nil {
+		t.Fatalf("ConcatMessages failed: %v", err)
+	}
+	return msg.ToolCalls
+}
+
+func TestChatModel_StreamParallelSameIndex(t *testing.T) {
+	// 部分兼容接口的并行调用全部使用 index 0, 第二个调用没有参数
+	calls := streamToolCalls(t,
+		call(intPtr(0), "call_a", "get_weather", ""),
+		call(intPtr(0), "", "", `{"city":`),
+		call(intPtr(0), "", "", `"Paris"}`),
+		call(intPtr(0), "call_b", "get_time", ""),
+	)
+	if len(calls) != 2 {
+		t.Fatalf("unexpected tool calls: %+v", calls)
+	}
+	first, second := calls[0], calls[1]
+	if *first.Index != 0 || first.ID != "call_a" || first.Function.Name != "get_weather" || first.Function.Arguments != `{"city":"Paris"}` {
+		t.Fatalf("unexpected first tool call: %+v", first)
+	}
+	if *second.Index != 1 || second.ID != "call_b" || second.Function.Name != "get_time" || second.Function.Arguments != "{}" {
+		t.Fatalf("unexpected second tool call: %+v", second)
+	}
+}
+
+func TestChatModel_StreamWithoutIndex(t *testing.T) {
+	calls := streamToolCalls(t,
+		call(nil, "", "get_weather", `{"city":`),
+		call(nil, "", "", `"Paris"}`),
+		call(nil, "", "get_time", ""),
+	)
+	if len(calls) != 2 || calls[0].ID == "" || calls[0].ID == calls[1].ID || calls[0].Type != "function" {
+		t.Fatalf("unexpected tool calls: %+v", calls)
+	}
+	if calls[0].Function.Arguments != `{"city":"Paris"}` || calls[1].Function.Arguments != "{}" {
+		t.Fatalf("unexpected arguments: %+v", calls)
+	}
+}
+
+func TestChatModel_GenerateFillsMissingFields(t *testing.T) {
+	cm, _ := NewChatModel(&fakeChatModel{reply: &schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{
+		call(nil, "", "get_weather", `{"city":"Paris"}`),
+		call(nil, "", "get_time", " "),
+	}}})
+	resp, err := cm.Generate(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for i, tc := range resp.ToolCalls {
+		if tc.Index == nil || *tc.Index != i || tc.ID == "" || tc.Type != "function" || !json.Valid([]byte(tc.Function.Arguments)) {
+			t.Fatalf("unexpected tool call %d: %+v", i, tc)
+		}
+	}
+	if resp.ToolCalls[0].ID == resp.ToolCalls[1].ID {
+		t.Fatalf("tool call ids must differ: %+v", resp.ToolCalls)
+	}
+}
+
+func TestChatModel_WithTools(t *testing.T) {
+	inner := &fakeChatModel{}
+	cm, _ := NewChatModel(inner)
+	weather := &schema.ToolInfo{Name: "get_weather", ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+		"city": {Type: schema.String, Required: true},
+	})}
+	if _, err := cm.WithTools([]*schema.ToolInfo{weather, {Name: "get_time"}}); err != nil {
+		t.Fatalf("WithTools failed: %v", err)
+	}
+	js, err := inner.tools[1].ParamsOneOf.ToJSONSchema()
+	if err != nil || js == nil || js.Type != "object" {
+		t.Fatalf("tool without params should get an empty object schema: %+v %v", js, err)
+	}
+
+	for name, tools := range map[string][]*schema.ToolInfo{
+		"duplicate": {weather, weather},
+		"empty":     {{Name: ""}},
+		"none":      nil,
+	} {
+		if _, err := cm.WithTools(tools); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
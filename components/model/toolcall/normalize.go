@@ -0,0 +1,97 @@
+package toolcall
+
+import (
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/google/uuid"
+)
+
+// normalizeToolCalls 非流式结果按顺序编号, 并补全缺失的 ID、Type 与参数
+func normalizeToolCalls(calls []schema.ToolCall) {
+	for i := range calls {
+		idx := i
+		tc := &calls[i]
+		tc.Index = &idx
+		if tc.ID == "" {
+			tc.ID = newCallID()
+		}
+		if tc.Type == "" {
+			tc.Type = "function"
+		}
+		if strings.TrimSpace(tc.Function.Arguments) == "" {
+			tc.Function.Arguments = "{}"
+		}
+	}
+}
+
+// tracker 为流式分块中的工具调用分配统一的 Index.
+// 上游带 Index 时按 Index 归并, 同一 Index 出现不同 ID 时视为新的调用(部分兼容接口并行调用全部使用 0);
+// 上游不带 Index 时, 带 ID 或函数名的分块开始新的调用, 其余分块追加到上一个调用
+type tracker struct {
+	byIndex map[int]int
+	ids     []string
+	hasArgs []bool
+}
+
+func (t *tracker) feed(calls []schema.ToolCall) {
+	for i := range calls {
+		tc := &calls[i]
+		idx, isNew := t.resolve(tc)
+		if isNew {
+			tc.ID = t.ids[idx]
+			if tc.Type == "" {
+				tc.Type = "function"
+			}
+		}
+		tc.Index = &idx
+		if strings.TrimSpace(tc.Function.Arguments) != "" {
+			t.hasArgs[idx] = true
+		}
+	}
+}
+
+func (t *tracker) resolve(tc *schema.ToolCall) (int, bool) {
+	if tc.Index != nil {
+		if cur, ok := t.byIndex[*tc.Index]; ok && (tc.ID == "" || t.ids[cur] == tc.ID) {
+			return cur, false
+		}
+		idx := t.add(tc.ID)
+		if t.byIndex == nil {
+			t.byIndex = map[int]int{}
+		}
+		t.byIndex[*tc.Index] = idx
+		return idx, true
+	}
+	start := tc.ID != "" || tc.Function.Name != ""
+	if n := len(t.ids); n > 0 && (!start || (tc.ID != "" && t.ids[n-1] == tc.ID)) {
+		return n - 1, false
+	}
+	return t.add(tc.ID), true
+}
+
+func (t *tracker) add(id string) int {
+	if id == "" {
+		id = newCallID()
+	}
+	t.ids = append(t.ids, id)
+	t.hasArgs = append(t.hasArgs, false)
+	return len(t.ids) - 1
+}
+
+// flush 流结束时为没有参数的调用补全空对象, 拼接后的参数始终是合法 JSON
+func (t *tracker) flush() []schema.ToolCall {
+	var calls []schema.ToolCall
+	for i, ok := range t.hasArgs {
+		if ok {
+			continue
+		}
+		idx := i
+		calls = append(calls, schema.ToolCall{Index: &idx, Function: schema.FunctionCall{Arguments: "{}"}})
+	}
+	return calls
+}
+
+func newCallID() string {
+	return "call_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
@@ -0,0 +1,161 @@
+package ark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/samber/lo"
+)
+
+const defaultEndpoint = "https://api-knowledgebase.mlp.cn-beijing.volces.com/api/knowledge/service/rerank"
+
+// Reranker 火山方舟知识库 rerank 服务
+type Reranker struct {
+	Ctx    context.Context
+	Config RerankerConfig
+}
+
+type RerankerConfig struct {
+	// 知识库 API Key, 与方舟推理的 API Key 不通用
+	APIKey string
+	// rerank_model, 例如 base-multilingual-rerank、m3-v2-rerank
+	Model string
+	// 知识库服务地址, 为空时使用默认地址, 不能是方舟推理地址
+	BaseUrl    string
+	HTTPClient *http.Client
+}
+
+type RerankData struct {
+	Query   string `json:"query"`
+	Content string `json:"content"`
+	Title   string `json:"title,omitempty"`
+}
+
+type RerankRequest struct {
+	Datas       []RerankData `json:"datas"`
+	RerankModel string       `json:"rerank_model,omitempty"`
+}
+
+type RerankResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    *struct {
+		Scores     []float64 `json:"scores"`
+		TokenUsage int       `json:"token_usage"`
+	} `json:"data,omitempty"`
+}
+
+func NewReranker(ctx context.Context, config RerankerConfig) (*Reranker, error) {
+	if config.APIKey == "" {
+		return nil, errors.New("missing volcengine knowledge base api key")
+	}
+	baseUrl, err := normalizeBaseUrl(config.BaseUrl)
+	if err != nil {
+		return nil, err
+	}
+	config.BaseUrl = baseUrl
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &Reranker{
+		Ctx:    ctx,
+		Config: config,
+	}, nil
+}
+
+// normalizeBaseUrl 补全 rerank 路径, 以 # 结尾时原样使用.
+// 知识库服务与方舟推理使用不同域名和鉴权, 方舟推理地址直接报错
+func normalizeBaseUrl(u string) (string, error) {
+	if u == "" {
+		return defaultEndpoint, nil
+	}
+	if strings.HasSuffix(u, "#") {
+		return strings.TrimSuffix(u, "#"), nil
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return "", fmt.Errorf("invalid volcengine knowledge base url: %w", err)
+	}
+	if strings.HasPrefix(parsed.Host, "ark.") {
+		return "", fmt.Errorf("%s is the ark inference endpoint, knowledge base rerank requires the knowledge base endpoint such as %s", parsed.Host, defaultEndpoint)
+	}
+	u = strings.TrimSuffix(u, "/")
+	if !strings.HasSuffix(u, "/rerank") {
+		return u + "/api/knowledge/service/rerank", nil
+	}
+	return u, nil
+}
+
+func (r *Reranker) Rerank(ctx context.Context, req domain.RerankRequest) (domain.RerankResponse, error) {
+	if len(req.Documents) == 0 || r.Config.BaseUrl == "" {
+		return domain.RerankResponse{}, errors.New("invalid params")
+	}
+
+	body := RerankRequest{
+		Datas: lo.Map(req.Documents, func(doc string, _ int) RerankData {
+			return RerankData{Query: req.Query, Content: doc}
+		}),
+		RerankModel: r.Config.Model,
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return domain.RerankResponse{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Config.BaseUrl, bytes.NewReader(raw))
+	if err != nil {
+		return domain.RerankResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+r.Config.APIKey)
+
+	rawResp, err := r.Config.HTTPClient.Do(httpReq)
+	if err != nil {
+		return domain.RerankResponse{}, err
+	}
+	defer func() { _ = rawResp.Body.Close() }()
+
+	var resp RerankResponse
+	decodeErr := json.NewDecoder(rawResp.Body).Decode(&resp)
+	if rawResp.StatusCode != http.StatusOK || (decodeErr == nil && resp.Code != 0) {
+		if resp.Message != "" {
+			return domain.RerankResponse{}, fmt.Errorf("%s: %s", rawResp.Status, resp.Message)
+		}
+		return domain.RerankResponse{}, errors.New(rawResp.Status)
+	}
+	if decodeErr != nil {
+		return domain.RerankResponse{}, decodeErr
+	}
+	if resp.Data == nil || len(resp.Data.Scores) != len(req.Documents) {
+		return domain.RerankResponse{}, errors.New("empty results")
+	}
+
+	// 接口按输入顺序返回分数, 需要自行排序
+	results := make([]domain.Result, 0, len(resp.Data.Scores))
+	for i, score := range resp.Data.Scores {
+		result := domain.Result{Index: i, RelevanceScore: score}
+		if req.ReturnDocuments {
+			result.Document = req.Documents[i]
+		}
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].RelevanceScore > results[j].RelevanceScore })
+	if req.N != nil {
+		n := min(max(*req.N, 1), len(results))
+		results = results[:n]
+	}
+
+	rerankResp := domain.RerankResponse{Results: results}
+	if resp.Data.TokenUsage > 0 {
+		rerankResp.Usage = &domain.Usage{PromptTokens: resp.Data.TokenUsage, TotalTokens: resp.Data.TokenUsage}
+	}
+	return rerankResp, nil
+}
@@ -0,0 +1,225 @@
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/samber/lo"
+)
+
+// Reranker Cohere v2 rerank 协议实现, 适用于 Cohere 以及 LiteLLM、Xinference 等兼容网关
+type Reranker struct {
+	Ctx    context.Context
+	Config RerankerConfig
+}
+
+type RerankerConfig struct {
+	APIKey     string
+	Model      string
+	BaseUrl    string
+	HTTPClient *http.Client
+}
+
+type RerankRequest struct {
+	Model           string   `json:"model"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	TopN            *int     `json:"top_n,omitempty"`
+	MaxTokensPerDoc *int     `json:"max_tokens_per_doc,omitempty"`
+}
+
+type RerankResponse struct {
+	ID      string         `json:"id"`
+	Results []RerankResult `json:"results"`
+	Meta    *struct {
+		BilledUnits *struct {
+			SearchUnits  int `json:"search_units"`
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"billed_units,omitempty"`
+		Tokens *struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"tokens,omitempty"`
+	} `json:"meta,omitempty"`
+	// LiteLLM/Xinference 等网关返回 OpenAI 风格 usage
+	Usage   *RerankUsage `json:"usage,omitempty"`
+	Message string       `json:"message,omitempty"`
+}
+
+type RerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+	// v1 风格网关可能返回 {"text": "..."} 或字符串
+	Document json.RawMessage `json:"document,omitempty"`
+}
+
+type RerankUsage struct {
+	PromptTokens int `json:"prompt_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+func NewReranker(ctx context.Context, config RerankerConfig) *Reranker {
+	config.BaseUrl = normalizeBaseUrl(config.BaseUrl)
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &Reranker{
+		Ctx:    ctx,
+		Config: config,
+	}
+}
+
+// normalizeBaseUrl 仅有域名时补全 /v2/rerank, 以版本号结尾时补全 /rerank
+func normalizeBaseUrl(u string) string {
+	if u == "" {
+		return "https://api.cohere.com/v2/rerank"
+	}
+	if strings.HasSuffix(u, "#") {
+		return strings.TrimSuffix(u, "#")
+	}
+	u = strings.TrimSuffix(u, "/")
+	if strings.HasSuffix(u, "/rerank") {
+		return u
+	}
+	if parsed, err := url.Parse(u); err == nil && parsed.Path == "" {
+		return u + "/v2/rerank"
+	}
+	return u + "/rerank"
+}
+
+// yamlDocument 按 Cohere 建议将结构化文档序列化为 YAML, 值使用 JSON 表示(JSON 是 YAML 的子集)
+func yamlDocument(doc map[string]any) (string, error) {
+	keys := lo.Keys(doc)
+	slices.Sort(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		v, err := json.Marshal(doc[k])
+		if err != nil {
+			return "", err
+		}
+		key, _ := json.Marshal(k)
+		sb.Write(key)
+		sb.WriteString(": ")
+		sb.Write(v)
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+func documentText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var doc struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &doc); err == nil {
+		return doc.Text
+	}
+	var text string
+	_ = json.Unmarshal(raw, &text)
+	return text
+}
+
+func (r *Reranker) Rerank(ctx context.Context, req domain.RerankRequest) (domain.RerankResponse, error) {
+	documents := req.Documents
+	if len(req.StructuredDocuments) > 0 {
+		documents = make([]string, 0, len(req.StructuredDocuments))
+		for _, doc := range req.StructuredDocuments {
+			text, err := yamlDocument(doc)
+			if err != nil {
+				return domain.RerankResponse{}, err
+			}
+			documents = append(documents, text)
+		}
+	}
+	if len(documents) == 0 || r.Config.Model == "" || r.Config.BaseUrl == "" {
+		return domain.RerankResponse{}, errors.New("invalid params")
+	}
+
+	var topN *int
+	if req.N != nil {
+		n := min(max(*req.N, 1), len(documents))
+		topN = &n
+	}
+
+	body := RerankRequest{
+		Model:           r.Config.Model,
+		Query:           req.Query,
+		Documents:       documents,
+		TopN:            topN,
+		MaxTokensPerDoc: req.MaxTokensPerDoc,
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return domain.RerankResponse{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Config.BaseUrl, bytes.NewReader(raw))
+	if err != nil {
+		return domain.RerankResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+r.Config.APIKey)
+
+	rawResp, err := r.Config.HTTPClient.Do(httpReq)
+	if err != nil {
+		return domain.RerankResponse{}, err
+	}
+	defer func() { _ = rawResp.Body.Close() }()
+
+	var resp RerankResponse
+	decodeErr := json.NewDecoder(rawResp.Body).Decode(&resp)
+	if rawResp.StatusCode != http.StatusOK {
+		if resp.Message != "" {
+			return domain.RerankResponse{}, fmt.Errorf("%s: %s", rawResp.Status, resp.Message)
+		}
+		return domain.RerankResponse{}, errors.New(rawResp.Status)
+	}
+	if decodeErr != nil {
+		return domain.RerankResponse{}, decodeErr
+	}
+	if len(resp.Results) == 0 {
+		return domain.RerankResponse{}, errors.New("empty results")
+	}
+
+	var rerankResp domain.RerankResponse
+	rerankResp.Results = lo.Map(resp.Results, func(item RerankResult, _ int) domain.Result {
+		doc := documentText(item.Document)
+		// v2 不支持 return_documents, 响应不包含文档内容, 按下标回填
+		if doc == "" && req.ReturnDocuments && item.Index >= 0 && item.Index < len(documents) {
+			doc = documents[item.Index]
+		}
+		return domain.Result{
+			Index:          item.Index,
+			RelevanceScore: item.RelevanceScore,
+			Document:       doc,
+		}
+	})
+	rerankResp.Usage = toUsage(&resp)
+	return rerankResp, nil
+}
+
+func toUsage(resp *RerankResponse) *domain.Usage {
+	switch {
+	case resp.Usage != nil:
+		return &domain.Usage{PromptTokens: resp.Usage.PromptTokens, TotalTokens: resp.Usage.TotalTokens}
+	case resp.Meta != nil && resp.Meta.Tokens != nil:
+		t := resp.Meta.Tokens
+		return &domain.Usage{InputTokens: t.InputTokens, OutputTokens: t.OutputTokens, TotalTokens: t.InputTokens + t.OutputTokens}
+	case resp.Meta != nil && resp.Meta.BilledUnits != nil:
+		b := resp.Meta.BilledUnits
+		return &domain.Usage{InputTokens: b.InputTokens, OutputTokens: b.OutputTokens, TotalTokens: b.InputTokens + b.OutputTokens}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,161 @@
+package jina
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/samber/lo"
+)
+
+type Reranker struct {
+	Ctx    context.Context
+	Config RerankerConfig
+}
+
+type RerankerConfig struct {
+	APIKey     string
+	Model      string
+	BaseUrl    string
+	HTTPClient *http.Client
+}
+
+type RerankRequest struct {
+	Model           string   `json:"model"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	TopN            *int     `json:"top_n,omitempty"`
+	ReturnDocuments bool     `json:"return_documents"`
+}
+
+type RerankResponse struct {
+	Model   string         `json:"model"`
+	Results []RerankResult `json:"results"`
+	Usage   *RerankUsage   `json:"usage,omitempty"`
+	Detail  string         `json:"detail,omitempty"`
+}
+
+type RerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+	// 文档可能为 {"text": "..."} 或字符串
+	Document json.RawMessage `json:"document,omitempty"`
+}
+
+type RerankUsage struct {
+	PromptTokens int `json:"prompt_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+func NewReranker(ctx context.Context, config RerankerConfig) *Reranker {
+	config.BaseUrl = normalizeBaseUrl(config.BaseUrl)
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &Reranker{
+		Ctx:    ctx,
+		Config: config,
+	}
+}
+
+func normalizeBaseUrl(u string) string {
+	if u == "" {
+		return "https://api.jina.ai/v1/rerank"
+	}
+	if strings.HasSuffix(u, "#") {
+		return strings.TrimSuffix(u, "#")
+	}
+	u = strings.TrimSuffix(u, "/")
+	if !strings.HasSuffix(u, "/rerank") {
+		return u + "/rerank"
+	}
+	return u
+}
+
+func documentText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var doc struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &doc); err == nil {
+		return doc.Text
+	}
+	var text string
+	_ = json.Unmarshal(raw, &text)
+	return text
+}
+
+func (r *Reranker) Rerank(ctx context.Context, req domain.RerankRequest) (domain.RerankResponse, error) {
+	if len(req.Documents) == 0 || r.Config.Model == "" || r.Config.BaseUrl == "" {
+		return domain.RerankResponse{}, errors.New("invalid params")
+	}
+
+	var topN *int
+	if req.N != nil {
+		n := min(max(*req.N, 1), len(req.Documents))
+		topN = &n
+	}
+
+	body := RerankRequest{
+		Model:           r.Config.Model,
+		Query:           req.Query,
+		Documents:       req.Documents,
+		TopN:            topN,
+		ReturnDocuments: req.ReturnDocuments,
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return domain.RerankResponse{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Config.BaseUrl, bytes.NewReader(raw))
+	if err != nil {
+		return domain.RerankResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+r.Config.APIKey)
+
+	rawResp, err := r.Config.HTTPClient.Do(httpReq)
+	if err != nil {
+		return domain.RerankResponse{}, err
+	}
+	defer func() { _ = rawResp.Body.Close() }()
+
+	var resp RerankResponse
+	decodeErr := json.NewDecoder(rawResp.Body).Decode(&resp)
+	if rawResp.StatusCode != http.StatusOK {
+		if resp.Detail != "" {
+			return domain.RerankResponse{}, fmt.Errorf("%s: %s", rawResp.Status, resp.Detail)
+		}
+		return domain.RerankResponse{}, errors.New(rawResp.Status)
+	}
+	if decodeErr != nil {
+		return domain.RerankResponse{}, decodeErr
+	}
+	if len(resp.Results) == 0 {
+		return domain.RerankResponse{}, errors.New("empty results")
+	}
+
+	var rerankResp domain.RerankResponse
+	rerankResp.Results = lo.Map(resp.Results, func(item RerankResult, _ int) domain.Result {
+		return domain.Result{
+			Index:          item.Index,
+			RelevanceScore: item.RelevanceScore,
+			Document:       documentText(item.Document),
+		}
+	})
+	if resp.Usage != nil {
+		rerankResp.Usage = &domain.Usage{
+			PromptTokens: resp.Usage.PromptTokens,
+			TotalTokens:  resp.Usage.TotalTokens,
+		}
+	}
+	return rerankResp, nil
+}
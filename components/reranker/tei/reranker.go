@@ -0,0 +1,120 @@
+package tei
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/chaitin/ModelKit/v2/components/embedder/tei"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/samber/lo"
+)
+
+// 未能读取 /info 时使用的默认单次请求条数
+const defaultMaxBatch = 32
+
+type Reranker struct {
+	Ctx    context.Context
+	Config RerankerConfig
+
+	httpClient *http.Client
+	info       tei.InfoCache
+}
+
+type RerankerConfig struct {
+	// 启动 TEI 时设置了 --api-key 才需要
+	APIKey string
+	// TEI 每个实例只部署一个模型, 仅用于展示
+	Model      string
+	BaseUrl    string
+	HTTPClient *http.Client
+}
+
+type RerankRequest struct {
+	Query      string   `json:"query"`
+	Texts      []string `json:"texts"`
+	ReturnText bool     `json:"return_text"`
+	// 超过 /info 中 max_input_length 的文本由服务端截断而不是报错
+	Truncate bool `json:"truncate"`
+}
+
+type RerankResult struct {
+	Index int     `json:"index"`
+	Score float64 `json:"score"`
+	Text  string  `json:"text,omitempty"`
+}
+
+func NewReranker(ctx context.Context, config RerankerConfig) *Reranker {
+	config.BaseUrl = tei.NormalizeBaseURL(config.BaseUrl)
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Reranker{
+		Ctx:        ctx,
+		Config:     config,
+		httpClient: httpClient,
+	}
+}
+
+// batchSize 从 /info 读取 max_client_batch_size, 读取失败时使用默认值并在下次调用重试
+func (r *Reranker) batchSize(ctx context.Context) int {
+	if info, err := r.info.Get(ctx, r.httpClient, r.Config.BaseUrl, r.Config.APIKey); err == nil && info.MaxClientBatchSize > 0 {
+		return info.MaxClientBatchSize
+	}
+	return defaultMaxBatch
+}
+
+func (r *Reranker) Rerank(ctx context.Context, req domain.RerankRequest) (domain.RerankResponse, error) {
+	if len(req.Documents) == 0 || r.Config.BaseUrl == "" {
+		return domain.RerankResponse{}, errors.New("invalid params")
+	}
+
+	// 各文档的分数相互独立, 超过批量限制时分批请求后合并排序
+	var (
+		results []RerankResult
+		tokens  int
+	)
+	batch := r.batchSize(ctx)
+	for start := 0; start < len(req.Documents); start += batch {
+		end := min(start+batch, len(req.Documents))
+		var part []RerankResult
+		n, err := tei.Do(ctx, r.httpClient, http.MethodPost, r.Config.BaseUrl+"/rerank", r.Config.APIKey, RerankRequest{
+			Query:      req.Query,
+			Texts:      req.Documents[start:end],
+			ReturnText: req.ReturnDocuments,
+			Truncate:   true,
+		}, &part)
+		if err != nil {
+			return domain.RerankResponse{}, err
+		}
+		for _, item := range part {
+			item.Index += start
+			results = append(results, item)
+		}
+		tokens += n
+	}
+	if len(results) == 0 {
+		return domain.RerankResponse{}, errors.New("empty results")
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+
+	if req.N != nil {
+		n := min(max(*req.N, 1), len(results))
+		results = results[:n]
+	}
+
+	var rerankResp domain.RerankResponse
+	rerankResp.Results = lo.Map(results, func(item RerankResult, _ int) domain.Result {
+		return domain.Result{
+			Index:          item.Index,
+			RelevanceScore: item.Score,
+			Document:       item.Text,
+		}
+	})
+	if tokens > 0 {
+		rerankResp.Usage = &domain.Usage{PromptTokens: tokens, TotalTokens: tokens}
+	}
+	return rerankResp, nil
+}
@@ -0,0 +1,49 @@
+package tei
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+func TestReranker_MergesBatches(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"GET /info": standin.JSON(`{"model_id":"BAAI/bge-reranker-v2-m3","max_client_batch_size":2,"model_type":{"reranker":{}}}`),
+		"POST /rerank": func(w http.ResponseWriter, r *http.Request) {
+			var body RerankRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Texts) > 2 || !body.Truncate {
+				t.Errorf("unexpected batch: %+v", body)
+			}
+			// 分数为文本长度, 批内下标从 0 开始
+			out := make([]RerankResult, len(body.Texts))
+			for i, text := range body.Texts {
+				out[i] = RerankResult{Index: i, Score: float64(len(text)), Text: text}
+			}
+			w.Header().Set("X-Compute-Tokens", "5")
+			standin.WriteJSON(w, out)
+		},
+	})
+
+	rk := NewReranker(context.Background(), RerankerConfig{BaseUrl: ts.URL})
+	n := 2
+	resp, err := rk.Rerank(context.Background(), domain.RerankRequest{
+		Query:           "q",
+		Documents:       []string{"a", "bb", "cccc"},
+		ReturnDocuments: true,
+		N:               &n,
+	})
+	if err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Index != 2 || resp.Results[0].Document != "cccc" || resp.Results[1].Index != 1 {
+		t.Fatalf("unexpected rerank result: %+v", resp)
+	}
+	if resp.Usage == nil || resp.Usage.TotalTokens != 10 {
+		t.Fatalf("unexpected usage: %+v", resp.Usage)
+	}
+}
@@ -41,6 +41,15 @@ func ParseModelType(s string) ModelType {
 	}
 }
 
+// 模型能力, 与 Ollama /api/show 返回的 capabilities 一致
+const (
+	ModelCapabilityCompletion = "completion"
+	ModelCapabilityVision     = "vision"
+	ModelCapabilityTools      = "tools"
+	ModelCapabilityEmbedding  = "embedding"
+	ModelCapabilityThinking   = "thinking"
+)
+
 type ModelProvider string
 
 const (
@@ -98,6 +107,7 @@ const (
 	ModelProviderVoyageAI              ModelProvider = "VoyageAI"
 	ModelProviderAWSBedrock            ModelProvider = "AWSBedrock"
 	ModelProviderPoe                   ModelProvider = "Poe"
+	ModelProviderHuggingFaceTEI        ModelProvider = "HuggingFaceTEI"
 	ModelProviderOther                 ModelProvider = "Other"
 )
 
@@ -208,15 +218,33 @@ func ParseModelProvider(s string) ModelProvider {
 		return ModelProviderGPUStack
 	case "voyageai":
 		return ModelProviderVoyageAI
-	case "aws-bedrock":
+	case "awsbedrock", "aws-bedrock", "bedrock":
 		return ModelProviderAWSBedrock
 	case "poe":
 		return ModelProviderPoe
+	case "huggingfacetei", "huggingface-tei", "tei":
+		return ModelProviderHuggingFaceTEI
 	default:
 		return ModelProviderOther
 	}
 }
 
+// RerankProtocol 重排序接口协议, 为空时按提供商选择
+type RerankProtocol string
+
+const (
+	RerankProtocolAuto   RerankProtocol = ""
+	RerankProtocolCohere RerankProtocol = "cohere" // Cohere v2 rerank, 兼容 LiteLLM、Xinference 等网关
+)
+
+// ChatAPI 对话接口协议, 为空时使用 Chat Completions
+type ChatAPI string
+
+const (
+	ChatAPIChatCompletions ChatAPI = ""
+	ChatAPIResponses       ChatAPI = "responses" // OpenAI Responses API, 仅 OpenAI 与 AzureOpenAI 支持
+)
+
 var ApiKeyBalanceKeyWords = []string{"quota", "billing", "balance", "payment required"}
 
 type AddModelBaseURLErrType string
@@ -0,0 +1,24 @@
+package domain
+
+type AnthropicModel struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	DisplayName string `json:"display_name"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type AnthropicResp struct {
+	Data    []*AnthropicModel `json:"data"`
+	HasMore bool              `json:"has_more"`
+	FirstID string            `json:"first_id"`
+	LastID  string            `json:"last_id"`
+}
+
+// ParseModels 实现ModelResponseParser接口
+func (a *AnthropicResp) ParseModels() []ModelListItem {
+	var models []ModelListItem
+	for _, item := range a.Data {
+		models = append(models, ModelListItem{Model: item.ID})
+	}
+	return models
+}
@@ -0,0 +1,27 @@
+package domain
+
+type AzureDeployment struct {
+	ID     string `json:"id"`
+	Model  string `json:"model"`
+	Owner  string `json:"owner"`
+	Status string `json:"status"`
+	Object string `json:"object"`
+}
+
+// AzureDeploymentsResp Azure OpenAI 数据面 /openai/deployments 响应
+type AzureDeploymentsResp struct {
+	Data []*AzureDeployment `json:"data"`
+}
+
+// ParseModels 实现ModelResponseParser接口, Model 为部署名称, BaseModel 为部署的底层模型
+func (a *AzureDeploymentsResp) ParseModels() []ModelListItem {
+	var models []ModelListItem
+	for _, item := range a.Data {
+		// 创建中或失败的部署无法调用
+		if item.Status != "" && item.Status != "succeeded" {
+			continue
+		}
+		models = append(models, ModelListItem{Model: item.ID, BaseModel: item.Model})
+	}
+	return models
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "slices"
+
+type BedrockFoundationModel struct {
+	ModelID                    string   `json:"modelId"`
+	ModelArn                   string   `json:"modelArn"`
+	ModelName                  string   `json:"modelName"`
+	ProviderName               string   `json:"providerName"`
+	InputModalities            []string `json:"inputModalities"`
+	OutputModalities           []string `json:"outputModalities"`
+	ResponseStreamingSupported bool     `json:"responseStreamingSupported"`
+	InferenceTypesSupported    []string `json:"inferenceTypesSupported"`
+}
+
+type BedrockListModelResp struct {
+	ModelSummaries []*BedrockFoundationModel `json:"modelSummaries"`
+}
+
+// ParseModels 实现ModelResponseParser接口, 仅保留支持按需调用的模型
+func (b *BedrockListModelResp) ParseModels() []ModelListItem {
+	var models []ModelListItem
+	for _, item := range b.ModelSummaries {
+		if len(item.InferenceTypesSupported) > 0 && !slices.Contains(item.InferenceTypesSupported, "ON_DEMAND") {
+			continue
+		}
+		models = append(models, ModelListItem{Model: item.ModelID})
+	}
+	return models
+}
+
+type BedrockInferenceProfile struct {
+	InferenceProfileID   string `json:"inferenceProfileId"`
+	InferenceProfileName string `json:"inferenceProfileName"`
+	Status               string `json:"status"`
+	Type                 string `json:"type"`
+}
+
+type BedrockListInferenceProfileResp struct {
+	InferenceProfileSummaries []*BedrockInferenceProfile `json:"inferenceProfileSummaries"`
+}
+
+// ParseModels 实现ModelResponseParser接口
+func (b *BedrockListInferenceProfileResp) ParseModels() []ModelListItem {
+	var models []ModelListItem
+	for _, item := range b.InferenceProfileSummaries {
+		if item.Status != "" && item.Status != "ACTIVE" {
+			continue
+		}
+		models = append(models, ModelListItem{Model: item.InferenceProfileID})
+	}
+	return models
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"slices"
+
 	"github.com/chaitin/ModelKit/v2/consts"
 )
 
@@ -14,6 +16,20 @@ type ModelListReq struct {
 	APIKey    string `json:"api_key" query:"api_key"`
 	APIHeader string `json:"api_header" query:"api_header"`
 	Type      string `json:"type" query:"type" validate:"required"`
+	// for azure openai
+	APIVersion string `json:"api_version" query:"api_version"`
+	// for aws bedrock
+	AWSAccessKeyID     string `json:"aws_access_key_id" query:"aws_access_key_id"`
+	AWSSecretAccessKey string `json:"aws_secret_access_key" query:"aws_secret_access_key"`
+	AWSSessionToken    string `json:"aws_session_token" query:"aws_session_token"`
+	AWSRegion          string `json:"aws_region" query:"aws_region"`
+	// for vertex ai
+	VertexProject        string `json:"vertex_project" query:"vertex_project"`
+	VertexLocation       string `json:"vertex_location" query:"vertex_location"`
+	VertexServiceAccount string `json:"vertex_service_account" query:"vertex_service_account"`
+	// for volcengine ark, 用于调用管控面 ListEndpoints 列出推理接入点
+	VolcengineAccessKey string `json:"volcengine_access_key" query:"volcengine_access_key"`
+	VolcengineSecretKey string `json:"volcengine_secret_key" query:"volcengine_secret_key"`
 }
 
 type Response struct {
@@ -29,10 +45,46 @@ type ModelListResp struct {
 
 type ModelListItem struct {
 	Model string `json:"model"`
+	// 部署名称与模型不一致时的底层模型, 例如 Azure OpenAI 部署对应的模型, 按名称过滤类型时优先使用
+	BaseModel string `json:"base_model,omitempty"`
+
+	// 以下为可选元数据, 仅部分供应商返回
+	Size              int64  `json:"size,omitempty"`
+	Family            string `json:"family,omitempty"`
+	ParameterSize     string `json:"parameter_size,omitempty"`
+	QuantizationLevel string `json:"quantization_level,omitempty"`
+	ContextLength     int    `json:"context_length,omitempty"`
+	MaxOutputTokens   int    `json:"max_output_tokens,omitempty"`
+	EmbeddingLength   int    `json:"embedding_length,omitempty"`
+	// 输入输出模态, 例如 text、image、audio、embeddings
+	InputModalities  []string `json:"input_modalities,omitempty"`
+	OutputModalities []string `json:"output_modalities,omitempty"`
+	// 模型能力, 取值见 consts.ModelCapability*, 非空时按能力过滤模型类型
+	Capabilities []string `json:"capabilities,omitempty"`
+	// 服务端声明的模型类别, 例如 GPUStack 的 llm、embedding、reranker, 非空时按类别过滤模型类型
+	Categories     []string `json:"categories,omitempty"`
+	Backend        string   `json:"backend,omitempty"`
+	BackendVersion string   `json:"backend_version,omitempty"`
+	// 单价, 单位为美元每 token(图片、请求为每次)
+	Pricing *ModelPricing `json:"pricing,omitempty"`
+	// 模型支持的请求参数, 例如 tools、reasoning、response_format
+	SupportedParameters []string `json:"supported_parameters,omitempty"`
+}
+
+// ModelPricing 与 OpenRouter 一致使用字符串表示价格, 避免精度丢失
+type ModelPricing struct {
+	Prompt            string `json:"prompt,omitempty"`
+	Completion        string `json:"completion,omitempty"`
+	Request           string `json:"request,omitempty"`
+	Image             string `json:"image,omitempty"`
+	WebSearch         string `json:"web_search,omitempty"`
+	InternalReasoning string `json:"internal_reasoning,omitempty"`
+	InputCacheRead    string `json:"input_cache_read,omitempty"`
+	InputCacheWrite   string `json:"input_cache_write,omitempty"`
 }
 
 type CheckModelReq struct {
-	Provider   string      `json:"provider" query:"provider" validate:"required,oneof=OpenAI Ollama DeepSeek SiliconFlow Moonshot Other AzureOpenAI BaiZhiCloud BaiZhiCloudModelStore Hunyuan BaiLian Volcengine Gemini ZhiPu AiHubMix"`
+	Provider   string      `json:"provider" query:"provider" validate:"required,oneof=OpenAI Ollama DeepSeek SiliconFlow Moonshot Other AzureOpenAI BaiZhiCloud BaiZhiCloudModelStore Hunyuan BaiLian Volcengine Gemini ZhiPu AiHubMix Anthropic AWSBedrock VertexAI Jina VoyageAI HuggingFaceTEI"`
 	Model      string      `json:"model" query:"model_name" validate:"required"`
 	BaseURL    string      `json:"base_url" query:"base_url" validate:"required"`
 	APIKey     string      `json:"api_key" query:"api_key"`
@@ -40,11 +92,31 @@ type CheckModelReq struct {
 	APIVersion string      `json:"api_version" query:"api_version"` // for azure openai
 	Type       string      `json:"type" query:"model_type" validate:"required,oneof=chat embedding rerank llm"`
 	Param      *ModelParam `json:"param" query:"param"`
+	// for aws bedrock
+	AWSAccessKeyID     string `json:"aws_access_key_id" query:"aws_access_key_id"`
+	AWSSecretAccessKey string `json:"aws_secret_access_key" query:"aws_secret_access_key"`
+	AWSSessionToken    string `json:"aws_session_token" query:"aws_session_token"`
+	AWSRegion          string `json:"aws_region" query:"aws_region"`
+	// for vertex ai
+	VertexProject        string `json:"vertex_project" query:"vertex_project"`
+	VertexLocation       string `json:"vertex_location" query:"vertex_location"`
+	VertexServiceAccount string `json:"vertex_service_account" query:"vertex_service_account"`
+	// for volcengine knowledge base rerank
+	VolcengineKnowledgeBaseURL string `json:"volcengine_knowledge_base_url" query:"volcengine_knowledge_base_url"`
+	VolcengineKnowledgeAPIKey  string `json:"volcengine_knowledge_api_key" query:"volcengine_knowledge_api_key"`
+	// for rerank
+	RerankProtocol string `json:"rerank_protocol" query:"rerank_protocol" validate:"omitempty,oneof=cohere"`
+	// for openai / azure openai chat
+	ChatAPI string `json:"chat_api" query:"chat_api" validate:"omitempty,oneof=responses"`
+	// 厂商扩展字段, 合并到对话请求体
+	ExtraBody map[string]any `json:"extra_body" query:"extra_body"`
 }
 
 type CheckModelResp struct {
 	Error   string `json:"error"`
 	Content string `json:"content"`
+	// 当前提供商会忽略的参数
+	Warnings []ParamWarning `json:"warnings,omitempty"`
 }
 
 func getModelsByOwner(owner consts.ModelProvider) []ModelMetadata {
@@ -102,7 +174,35 @@ type GithubResp []GithubModel
 func (g *GithubResp) ParseModels() []ModelListItem {
 	var models []ModelListItem
 	for _, item := range *g {
-		models = append(models, ModelListItem{Model: item.ID})
+		models = append(models, ModelListItem{
+			Model:            item.ID,
+			ContextLength:    item.Limits.MaxInputTokens,
+			MaxOutputTokens:  item.Limits.MaxOutputTokens,
+			InputModalities:  item.SupportedInputModalities,
+			OutputModalities: item.SupportedOutputModalities,
+			Capabilities:     item.normalizedCapabilities(),
+		})
 	}
 	return models
 }
+
+// normalizedCapabilities 将 GitHub Models 的能力与模态转换为 consts.ModelCapability*
+func (m *GithubModel) normalizedCapabilities() []string {
+	var caps []string
+	if slices.Contains(m.SupportedOutputModalities, "embeddings") {
+		caps = append(caps, consts.ModelCapabilityEmbedding)
+	}
+	if slices.Contains(m.SupportedOutputModalities, "text") {
+		caps = append(caps, consts.ModelCapabilityCompletion)
+	}
+	if slices.Contains(m.SupportedInputModalities, "image") {
+		caps = append(caps, consts.ModelCapabilityVision)
+	}
+	if slices.Contains(m.Capabilities, "tool-calling") {
+		caps = append(caps, consts.ModelCapabilityTools)
+	}
+	if slices.Contains(m.Capabilities, "reasoning") || slices.Contains(m.Tags, "reasoning") {
+		caps = append(caps, consts.ModelCapabilityThinking)
+	}
+	return caps
+}
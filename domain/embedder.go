@@ -15,6 +15,7 @@ type SparseEmbedding struct {
 type EmbeddingItem struct {
 	SparseEmbedding []SparseEmbedding `json:"sparse_embedding,omitempty"`
 	Embedding       []float64         `json:"embedding,omitempty"`
+	MultiEmbedding  [][]float64       `json:"multi_embedding,omitempty"`
 	TextIndex       int               `json:"text_index"`
 }
 
@@ -30,3 +31,15 @@ type EmbeddingsResponse struct {
 type EmbedderExt interface {
 	EmbedStringsExt(ctx context.Context, texts []string, opts ...embedding.Option) (*EmbeddingsResponse, error)
 }
+
+// EmbeddingInput 多模态向量输入, 同一条输入中的文本与图片融合为一个向量
+type EmbeddingInput struct {
+	Text string `json:"text,omitempty"`
+	// http(s) 图片地址或 data:image/<format>;base64,<data>
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// MultimodalEmbedder 支持图文混合输入的向量模型
+type MultimodalEmbedder interface {
+	EmbedInputs(ctx context.Context, inputs []EmbeddingInput, opts ...embedding.Option) (*EmbeddingsResponse, error)
+}
@@ -1,8 +1,22 @@
 package domain
 
+// GPUStack 模型类别
+const (
+	GPUStackCategoryLLM          = "llm"
+	GPUStackCategoryEmbedding    = "embedding"
+	GPUStackCategoryReranker     = "reranker"
+	GPUStackCategoryImage        = "image"
+	GPUStackCategorySpeechToText = "speech_to_text"
+	GPUStackCategoryTextToSpeech = "text_to_speech"
+)
+
+// GPUStackListModelResp GPUStack 管理接口 /v1/models 响应
 type GPUStackListModelResp struct {
 	Items []*struct {
-		Name string `json:"name"`
+		Name           string   `json:"name"`
+		Categories     []string `json:"categories"`
+		Backend        string   `json:"backend"`
+		BackendVersion string   `json:"backend_version"`
 	} `json:"items"`
 }
 
@@ -10,7 +24,12 @@ type GPUStackListModelResp struct {
 func (o *GPUStackListModelResp) ParseModels() []ModelListItem {
 	var models []ModelListItem
 	for _, item := range o.Items {
-		models = append(models, ModelListItem{Model: item.Name})
+		models = append(models, ModelListItem{
+			Model:          item.Name,
+			Categories:     item.Categories,
+			Backend:        item.Backend,
+			BackendVersion: item.BackendVersion,
+		})
 	}
 	return models
 }
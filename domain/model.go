@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"net/http"
+
 	"github.com/chaitin/ModelKit/v2/consts"
 	"github.com/cloudwego/eino-ext/libs/acl/openai"
 )
@@ -27,6 +29,20 @@ type ModelMetadata struct {
 	APIKey     string `json:"api_key"`
 	APIHeader  string `json:"api_header"`
 	APIVersion string `json:"api_version"` // for azure openai
+	// AWS Bedrock 鉴权参数, 未设置AccessKey时将APIKey作为Bedrock API Key使用
+	AWSAccessKeyID     string `json:"aws_access_key_id"`
+	AWSSecretAccessKey string `json:"aws_secret_access_key"`
+	AWSSessionToken    string `json:"aws_session_token"`
+	AWSRegion          string `json:"aws_region"`
+	// Vertex AI 鉴权参数, 未设置服务账号时将APIKey作为Vertex AI express模式的API Key使用
+	VertexProject        string `json:"vertex_project"`         // 为空时从服务账号中读取project_id
+	VertexLocation       string `json:"vertex_location"`        // 默认us-central1
+	VertexServiceAccount string `json:"vertex_service_account"` // 服务账号JSON密钥
+	// 火山方舟知识库 rerank 鉴权参数, 知识库服务与方舟推理的域名和API Key均不同
+	VolcengineKnowledgeBaseURL string `json:"volcengine_knowledge_base_url"` // 为空时使用默认知识库地址
+	VolcengineKnowledgeAPIKey  string `json:"volcengine_knowledge_api_key"`
+	// 自定义 HTTP 客户端, 用于代理或自定义 Transport, 目前 Gemini、火山方舟知识库 rerank、HuggingFace TEI 支持
+	HTTPClient *http.Client `json:"-"`
 	// 高级参数
 	// 限制生成的最大token数量,可选,默认为模型最大值, Ollama不支持
 	MaxTokens *int `json:"max_tokens"`
@@ -36,18 +52,36 @@ type ModelMetadata struct {
 	TopP *float32 `json:"top_p"`
 	// API停止生成的序列标记,可选,例如:[]string{"\n", "User:"}
 	Stop []string `json:"stop"`
-	// 基于存在惩罚重复,范围-2到2,正值增加新主题可能性,可选,默认0, Gemini不支持
+	// 基于存在惩罚重复,范围-2到2,正值增加新主题可能性,可选,默认0
 	PresencePenalty *float32 `json:"presence_penalty"`
-	// 指定模型响应的格式,可选,用于结构化输出, DS,Gemini,Ollama不支持
+	// 指定模型响应的格式,可选,用于结构化输出, DS,Ollama不支持, Gemini映射为responseMimeType/responseJsonSchema
 	ResponseFormat *openai.ChatCompletionResponseFormat `json:"response_format"`
-	// 启用确定性采样以获得一致输出,可选,用于可重现结果,  DS,Gemini不支持
+	// 启用确定性采样以获得一致输出,可选,用于可重现结果,  DS不支持
 	Seed *int `json:"seed"`
-	// 基于频率惩罚重复,范围-2到2,正值降低重复可能性,可选,默认0, Gemini不支持
+	// 基于频率惩罚重复,范围-2到2,正值降低重复可能性,可选,默认0
 	FrequencyPenalty *float32 `json:"frequency_penalty"`
 	// 修改特定token在补全中出现的可能性,可选,token ID到偏置值(-100到100)的映射, DS,Gemini,Ollama不支持
 	LogitBias map[string]int `json:"logit_bias"`
+	// 推理强度,可选,minimal、low、medium、high, OpenAI兼容接口映射为reasoning_effort, Ollama映射为think等级, Gemini 3映射为thinkingLevel
+	ReasoningEffort string `json:"reasoning_effort"`
+	// 是否开启思考,可选,默认使用模型默认值, 百炼/硅基流动映射为enable_thinking, DeepSeek切换deepseek-reasoner, Ollama映射为think, Gemini关闭时预算为0
+	EnableThinking *bool `json:"enable_thinking"`
+	// 思考预算(token),可选, 百炼/硅基流动映射为thinking_budget, Gemini映射为thinkingBudget, Anthropic映射为extended thinking预算
+	ThinkingBudget *int `json:"thinking_budget"`
+	// 厂商扩展字段,可选,深度合并到对话请求体,例如 {"enable_search": true}、{"options": {"num_ctx": 8192}},
+	// 值为 null 时删除对应字段,单次请求可通过 extrabody.WithContext 覆盖
+	ExtraBody map[string]any `json:"extra_body"`
+	// Gemini/Vertex AI 高级参数
+	GeminiParam GeminiParam `json:"gemini_param"`
+	// 对话接口协议,可选,为空时使用 Chat Completions,responses 表示使用 Responses API(仅 OpenAI、AzureOpenAI)
+	ChatAPI consts.ChatAPI `json:"chat_api"`
+	// Responses API 高级参数
+	ResponsesParam ResponsesParam `json:"responses_param"`
 	// Embeddng高级参数
 	EmbedderParam EmbedderParam `json:"embedder_param"`
+	// Rerank高级参数
+	// 重排序接口协议,可选,为空时按提供商选择,cohere 表示使用 Cohere v2 协议
+	RerankProtocol consts.RerankProtocol `json:"rerank_protocol"`
 }
 
 type EmbedderParam struct {
@@ -61,6 +95,44 @@ type EmbedderParam struct {
 	EncodingFormat *string `json:"encoding_format"`
 	// 检索指令，仅在 text-embedding-v4 且 TextType=query 时生效
 	Instruct *string `json:"instruct"`
+	// 任务类型，Jina 支持：retrieval.query、retrieval.passage、text-matching 等，未设置时由 TextType 推断
+	Task *string `json:"task"`
+	// 迟分块，仅 Jina 支持：先对全部输入整体编码再分块池化
+	LateChunking *bool `json:"late_chunking"`
+	// 多向量输出，Jina jina-embeddings-v4 与 jina-colbert 支持，结果写入 EmbeddingItem.MultiEmbedding
+	MultiVector *bool `json:"multi_vector"`
+	// 输出数据类型，仅 Voyage 支持：float、int8、uint8、binary、ubinary，默认 float
+	OutputDtype *string `json:"output_dtype"`
+	// 统计用量，仅 Gemini 支持：embedContent 不返回用量，开启后每批额外调用一次 countTokens，默认 false
+	CountTokens *bool `json:"count_tokens"`
+}
+
+type GeminiParam struct {
+	// 思考预算(token),可选,0 关闭思考,-1 由模型动态决定,不设置时使用模型默认值
+	ThinkingBudget *int32 `json:"thinking_budget"`
+	// 思考等级,可选,low 或 high,仅 Gemini 3 及以上支持,不能与 ThinkingBudget 同时使用
+	ThinkingLevel string `json:"thinking_level"`
+	// 是否返回思考摘要,可选,默认 true
+	IncludeThoughts *bool `json:"include_thoughts"`
+	// 各类有害内容的拦截阈值,可选,例如 {"HARM_CATEGORY_HARASSMENT": "BLOCK_NONE"},可省略 HARM_CATEGORY_ 前缀且不区分大小写
+	SafetySettings map[string]string `json:"safety_settings"`
+	// 响应 MIME 类型,可选,例如 text/plain、application/json、text/x.enum,优先于 ResponseFormat
+	ResponseMIMEType string `json:"response_mime_type"`
+	// 响应 JSON Schema,可选,设置后 MIME 类型默认为 application/json,优先于 ResponseFormat
+	ResponseSchema map[string]any `json:"response_schema"`
+}
+
+type ResponsesParam struct {
+	// 推理强度,可选,minimal、low、medium、high
+	ReasoningEffort string `json:"reasoning_effort"`
+	// 推理摘要,可选,auto、concise、detailed,结果写入 ReasoningContent
+	ReasoningSummary string `json:"reasoning_summary"`
+	// 内置工具,可选,原样透传,例如 {"type": "web_search"}
+	BuiltinTools []map[string]any `json:"builtin_tools"`
+	// 续接的上一次响应 ID,可选,取自回复消息 Extra 中的 openai_response_id
+	PreviousResponseID string `json:"previous_response_id"`
+	// 是否在服务端保存响应,可选,续接对话时需要保存
+	Store *bool `json:"store"`
 }
 
 var Models []ModelMetadata
@@ -230,6 +302,10 @@ func getVolcengineModels() []ModelMetadata {
 		{ModelName: "doubao-1.5-thinking-vision-pro-250428", Object: "model", Provider: consts.ModelProviderVolcengine, ModelType: consts.ModelTypeVision},
 		// {ModelName: "Doubao-1.5-thinking-pro-250415", Object: "model", Provider: consts.ModelProviderVolcengine, ModelType: consts.ModelTypeChat},
 		{ModelName: "deepseek-r1-250528", Object: "model", Provider: consts.ModelProviderVolcengine, ModelType: consts.ModelTypeChat},
+		{ModelName: "doubao-embedding-large-text-250515", Object: "model", Provider: consts.ModelProviderVolcengine, ModelType: consts.ModelTypeEmbedding},
+		{ModelName: "doubao-embedding-vision-250615", Object: "model", Provider: consts.ModelProviderVolcengine, ModelType: consts.ModelTypeEmbedding},
+		{ModelName: "base-multilingual-rerank", Object: "model", Provider: consts.ModelProviderVolcengine, ModelType: consts.ModelTypeRerank},
+		{ModelName: "m3-v2-rerank", Object: "model", Provider: consts.ModelProviderVolcengine, ModelType: consts.ModelTypeRerank},
 	}
 }
 
@@ -290,6 +290,11 @@ func initModelProviders() {
 			Models:    getModelsByOwner(consts.ModelProviderPoe),
 			APIBase:   "https://api.poe.com/v1",
 		},
+		consts.ModelProviderHuggingFaceTEI: {
+			OwnerName: consts.ModelProviderHuggingFaceTEI,
+			Models:    getModelsByOwner(consts.ModelProviderHuggingFaceTEI),
+			APIBase:   "http://localhost:8080",
+		},
 	}
 
 	// 初始化按类型分组的模型映射
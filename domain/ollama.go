@@ -0,0 +1,89 @@
+package domain
+
+import "strings"
+
+type OllamaModelDetails struct {
+	Format            string   `json:"format"`
+	Family            string   `json:"family"`
+	Families          []string `json:"families"`
+	ParameterSize     string   `json:"parameter_size"`
+	QuantizationLevel string   `json:"quantization_level"`
+}
+
+// OllamaTagsResp Ollama /api/tags 响应
+type OllamaTagsResp struct {
+	Models []struct {
+		Name       string             `json:"name"`
+		Model      string             `json:"model"`
+		ModifiedAt string             `json:"modified_at"`
+		Size       int64              `json:"size"`
+		Digest     string             `json:"digest"`
+		Details    OllamaModelDetails `json:"details"`
+	} `json:"models"`
+}
+
+// ParseModels 实现ModelResponseParser接口
+func (o *OllamaTagsResp) ParseModels() []ModelListItem {
+	var models []ModelListItem
+	for _, item := range o.Models {
+		name := item.Model
+		if name == "" {
+			name = item.Name
+		}
+		models = append(models, ModelListItem{
+			Model:             name,
+			Size:              item.Size,
+			Family:            item.Details.Family,
+			ParameterSize:     item.Details.ParameterSize,
+			QuantizationLevel: item.Details.QuantizationLevel,
+		})
+	}
+	return models
+}
+
+// OllamaShowResp Ollama /api/show 响应, capabilities 需要 Ollama 0.6.4 及以上
+type OllamaShowResp struct {
+	Details      OllamaModelDetails `json:"details"`
+	ModelInfo    map[string]any     `json:"model_info"`
+	Capabilities []string           `json:"capabilities"`
+}
+
+// ContextLength 读取 model_info 中的 <architecture>.context_length
+func (o *OllamaShowResp) ContextLength() int {
+	return o.modelInfoInt("context_length")
+}
+
+// EmbeddingLength 读取 model_info 中的 <architecture>.embedding_length
+func (o *OllamaShowResp) EmbeddingLength() int {
+	return o.modelInfoInt("embedding_length")
+}
+
+func (o *OllamaShowResp) modelInfoInt(key string) int {
+	arch, _ := o.ModelInfo["general.architecture"].(string)
+	if v, ok := o.ModelInfo[arch+"."+key].(float64); ok {
+		return int(v)
+	}
+	// 部分模型的 architecture 与前缀不一致
+	for k, v := range o.ModelInfo {
+		if f, ok := v.(float64); ok && strings.HasSuffix(k, "."+key) {
+			return int(f)
+		}
+	}
+	return 0
+}
+
+// Fill 将 /api/show 的结果补充到模型列表项
+func (o *OllamaShowResp) Fill(item *ModelListItem) {
+	item.ContextLength = o.ContextLength()
+	item.EmbeddingLength = o.EmbeddingLength()
+	item.Capabilities = o.Capabilities
+	if item.Family == "" {
+		item.Family = o.Details.Family
+	}
+	if item.ParameterSize == "" {
+		item.ParameterSize = o.Details.ParameterSize
+	}
+	if item.QuantizationLevel == "" {
+		item.QuantizationLevel = o.Details.QuantizationLevel
+	}
+}
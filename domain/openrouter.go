@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"slices"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+)
+
+type OpenRouterModel struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	ContextLength int    `json:"context_length"`
+	Architecture  struct {
+		Modality         string   `json:"modality"`
+		InputModalities  []string `json:"input_modalities"`
+		OutputModalities []string `json:"output_modalities"`
+		Tokenizer        string   `json:"tokenizer"`
+	} `json:"architecture"`
+	Pricing     *ModelPricing `json:"pricing"`
+	TopProvider struct {
+		ContextLength       int `json:"context_length"`
+		MaxCompletionTokens int `json:"max_completion_tokens"`
+	} `json:"top_provider"`
+	SupportedParameters []string `json:"supported_parameters"`
+}
+
+// OpenRouterResp OpenRouter /models 响应
+type OpenRouterResp struct {
+	Data []*OpenRouterModel `json:"data"`
+}
+
+// ParseModels 实现ModelResponseParser接口
+func (o *OpenRouterResp) ParseModels() []ModelListItem {
+	var models []ModelListItem
+	for _, item := range o.Data {
+		contextLength := item.ContextLength
+		if contextLength == 0 {
+			contextLength = item.TopProvider.ContextLength
+		}
+		models = append(models, ModelListItem{
+			Model:               item.ID,
+			ContextLength:       contextLength,
+			MaxOutputTokens:     item.TopProvider.MaxCompletionTokens,
+			InputModalities:     item.Architecture.InputModalities,
+			OutputModalities:    item.Architecture.OutputModalities,
+			Capabilities:        item.normalizedCapabilities(),
+			Pricing:             item.Pricing,
+			SupportedParameters: item.SupportedParameters,
+		})
+	}
+	return models
+}
+
+// normalizedCapabilities 将 OpenRouter 的模态与支持参数转换为 consts.ModelCapability*
+func (m *OpenRouterModel) normalizedCapabilities() []string {
+	var caps []string
+	if slices.Contains(m.Architecture.OutputModalities, "embeddings") {
+		caps = append(caps, consts.ModelCapabilityEmbedding)
+	}
+	if slices.Contains(m.Architecture.OutputModalities, "text") {
+		caps = append(caps, consts.ModelCapabilityCompletion)
+	}
+	if slices.Contains(m.Architecture.InputModalities, "image") {
+		caps = append(caps, consts.ModelCapabilityVision)
+	}
+	if slices.Contains(m.SupportedParameters, "tools") {
+		caps = append(caps, consts.ModelCapabilityTools)
+	}
+	if slices.Contains(m.SupportedParameters, "reasoning") {
+		caps = append(caps, consts.ModelCapabilityThinking)
+	}
+	return caps
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParamError 参数取值不合法
+type ParamError struct {
+	Param   string `json:"param"`
+	Value   any    `json:"value"`
+	Message string `json:"message"`
+}
+
+func (e ParamError) Error() string {
+	return fmt.Sprintf("%s %s, got %v", e.Param, e.Message, e.Value)
+}
+
+// ParamWarning 当前提供商不支持、构建客户端时会被忽略的参数
+type ParamWarning struct {
+	Param   string `json:"param"`
+	Message string `json:"message"`
+}
+
+func (w ParamWarning) String() string {
+	return w.Param + ": " + w.Message
+}
+
+// ParamValidationError 汇总全部取值错误, 可通过 errors.As 取得
+type ParamValidationError struct {
+	Errors []ParamError `json:"errors"`
+}
+
+func (e *ParamValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, pe := range e.Errors {
+		msgs = append(msgs, pe.Error())
+	}
+	return "invalid model params: " + strings.Join(msgs, "; ")
+}
+
+// ParamValidation 对话模型参数校验结果
+type ParamValidation struct {
+	Errors   []ParamError   `json:"errors,omitempty"`
+	Warnings []ParamWarning `json:"warnings,omitempty"`
+}
+
+// Err 存在取值错误时返回 *ParamValidationError
+func (v *ParamValidation) Err() error {
+	if len(v.Errors) == 0 {
+		return nil
+	}
+	return &ParamValidationError{Errors: v.Errors}
+}
@@ -11,6 +11,12 @@ type RerankRequest struct {
 	Documents       []string `json:"documents"`
 	Query           string   `json:"query"`
 	ReturnDocuments bool     `json:"return_documents"`
+	// 超长时是否截断, 仅 Voyage 支持, 为空时使用服务端默认值
+	Truncation *bool `json:"truncation,omitempty"`
+	// 每个文档的最大token数, 仅 Cohere 协议支持
+	MaxTokensPerDoc *int `json:"max_tokens_per_doc,omitempty"`
+	// 结构化文档, 仅 Cohere 协议支持, 设置后替代 Documents 并按 YAML 格式序列化
+	StructuredDocuments []map[string]any `json:"structured_documents,omitempty"`
 }
 
 type RerankResponse struct {
@@ -0,0 +1,38 @@
+package domain
+
+import "github.com/chaitin/ModelKit/v2/consts"
+
+// TEIInfo HuggingFace Text Embeddings Inference 的 /info 响应
+type TEIInfo struct {
+	ModelID            string `json:"model_id"`
+	ModelSHA           string `json:"model_sha,omitempty"`
+	ModelDtype         string `json:"model_dtype"`
+	MaxConcurrentReqs  int    `json:"max_concurrent_requests"`
+	MaxInputLength     int    `json:"max_input_length"`
+	MaxBatchTokens     int    `json:"max_batch_tokens"`
+	MaxBatchRequests   *int   `json:"max_batch_requests,omitempty"`
+	MaxClientBatchSize int    `json:"max_client_batch_size"`
+	Version            string `json:"version"`
+	// {"embedding": {...}}、{"reranker": {...}} 或 {"classifier": {...}}
+	ModelType map[string]any `json:"model_type"`
+}
+
+// Type 返回部署模型对应的类型, classifier 无法用于向量与重排序
+func (i *TEIInfo) Type() consts.ModelType {
+	switch {
+	case i.ModelType["reranker"] != nil:
+		return consts.ModelTypeRerank
+	case i.ModelType["embedding"] != nil:
+		return consts.ModelTypeEmbedding
+	default:
+		return ""
+	}
+}
+
+// ParseModels 实现ModelResponseParser接口, TEI 每个实例只部署一个模型, ContextLength 为 max_input_length
+func (i *TEIInfo) ParseModels() []ModelListItem {
+	if i.ModelID == "" {
+		return nil
+	}
+	return []ModelListItem{{Model: i.ModelID, ContextLength: i.MaxInputLength}}
+}
@@ -0,0 +1,165 @@
+// Package standin 提供测试用的 HTTP 替身服务, usecase 与各组件的测试共用
+package standin
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chaitin/ModelKit/v2/pkg/sigv4"
+)
+
+// Routes 键为 "/path" 或 "METHOD /path", 后者优先
+type Routes map[string]http.HandlerFunc
+
+// Check 在分发前校验请求, 返回错误时记为测试失败并响应 403
+type Check func(r *http.Request) error
+
+// New 启动替身服务, 未登记的路径记为测试失败并响应 404, 服务在测试结束时关闭
+func New(t testing.TB, routes Routes, checks ...Check) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, check := range checks {
+			if err := check(r); err != nil {
+				t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+		handler, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			handler, ok = routes[r.URL.Path]
+		}
+		if !ok {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// Header 校验请求头的值
+func Header(key, value string) Check {
+	return func(r *http.Request) error {
+		if got := r.Header.Get(key); got != value {
+			return fmt.Errorf("unexpected %s: %q", key, got)
+		}
+		return nil
+	}
+}
+
+// JSON 返回固定的 JSON 响应
+func JSON(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// WriteJSON 将 v 编码为 JSON 响应
+func WriteJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// WriteSSE 按 text/event-stream 依次写出 data 事件
+func WriteSSE(w http.ResponseWriter, events ...string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, ev := range events {
+		_, _ = w.Write([]byte("data: " + ev + "\n\n"))
+	}
+}
+
+// SigV4 按相同凭证重新签名并比较 Authorization, 校验后请求体可再次读取
+func SigV4(creds sigv4.Credentials, region, service string) Check {
+	signer, err := sigv4.NewSigner(creds, region, service)
+	return func(r *http.Request) error {
+		if err != nil {
+			return err
+		}
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		signedAt, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+		if err != nil {
+			return fmt.Errorf("missing X-Amz-Date: %w", err)
+		}
+		expected := r.Clone(r.Context())
+		expected.Header = http.Header{"Content-Type": r.Header.Values("Content-Type")}
+		signer.Sign(expected, body, signedAt)
+		if got, want := r.Header.Get("Authorization"), expected.Header.Get("Authorization"); got != want {
+			return fmt.Errorf("signature mismatch:\n got: %s\nwant: %s", got, want)
+		}
+		return nil
+	}
+}
+
+// EventMessage 按 application/vnd.amazon.eventstream 编码一条事件
+func EventMessage(eventType, payload string) []byte {
+	var headers bytes.Buffer
+	for _, h := range [][2]string{{":event-type", eventType}, {":message-type", "event"}, {":content-type", "application/json"}} {
+		headers.WriteByte(byte(len(h[0])))
+		headers.WriteString(h[0])
+		headers.WriteByte(7)
+		_ = binary.Write(&headers, binary.BigEndian, uint16(len(h[1])))
+		headers.WriteString(h[1])
+	}
+	total := 12 + headers.Len() + len(payload) + 4
+	var msg bytes.Buffer
+	_ = binary.Write(&msg, binary.BigEndian, uint32(total))
+	_ = binary.Write(&msg, binary.BigEndian, uint32(headers.Len()))
+	_ = binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	msg.Write(headers.Bytes())
+	msg.WriteString(payload)
+	_ = binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	return msg.Bytes()
+}
+
+// GoogleAuth 在 routes 中登记 OAuth2 令牌端点 /token, 以 JWT bearer 授权签发 token,
+// 返回的 Check 要求其余请求携带该 token
+func GoogleAuth(t testing.TB, routes Routes, token string) Check {
+	routes["POST /token"] = func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.Form.Get("assertion") == "" {
+			t.Errorf("unexpected token request: %v", r.Form)
+		}
+		WriteJSON(w, map[string]any{"access_token": token, "token_type": "Bearer", "expires_in": 3600})
+	}
+	bearer := Header("Authorization", "Bearer "+token)
+	return func(r *http.Request) error {
+		if r.URL.Path == "/token" {
+			return nil
+		}
+		return bearer(r)
+	}
+}
+
+// GoogleServiceAccount 生成使用新私钥、令牌端点为 tokenURI 的服务账号 JSON
+func GoogleServiceAccount(t testing.TB, project, tokenURI string) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	sa, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     project,
+		"private_key_id": "key-id",
+		"private_key":    string(keyPEM),
+		"client_email":   "modelkit@" + project + ".iam.gserviceaccount.com",
+		"token_uri":      tokenURI,
+	})
+	return string(sa)
+}
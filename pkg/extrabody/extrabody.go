@@ -0,0 +1,150 @@
+package extrabody
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// reservedFields 由适配器生成的核心字段, 不允许被扩展字段覆盖
+var reservedFields = []string{"model", "messages", "contents", "input", "stream"}
+
+type ctxKey struct{}
+
+// WithContext 为单次请求追加扩展字段, 与模型级字段深度合并且优先级更高
+func WithContext(ctx context.Context, extra map[string]any) context.Context {
+	if prev := FromContext(ctx); len(prev) > 0 {
+		extra = overlay(prev, extra)
+	}
+	return context.WithValue(ctx, ctxKey{}, extra)
+}
+
+// FromContext 返回 WithContext 设置的扩展字段
+func FromContext(ctx context.Context) map[string]any {
+	extra, _ := ctx.Value(ctxKey{}).(map[string]any)
+	return extra
+}
+
+// Merge 将 src 深度合并到 dst 并返回 dst, 两侧均为对象时递归合并, src 中值为 nil 的键会从 dst 中删除
+func Merge(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+	for k, v := range src {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+		if sm, ok := v.(map[string]any); ok {
+			dm, _ := dst[k].(map[string]any)
+			dst[k] = Merge(dm, sm)
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// overlay 按 Merge 的规则合并到 dst 的副本, 但保留 src 中值为 nil 的键, 删除留到写入请求体时生效,
+// 否则模型级的 null 会在与单次请求字段合并时丢失
+func overlay(dst, src map[string]any) map[string]any {
+	out := make(map[string]any, len(dst)+len(src))
+	for k, v := range dst {
+		out[k] = v
+	}
+	for k, v := range src {
+		if sm, ok := v.(map[string]any); ok {
+			dm, _ := out[k].(map[string]any)
+			out[k] = overlay(dm, sm)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// Validate 校验扩展字段可以序列化且没有覆盖保留字段
+func Validate(extra map[string]any) error {
+	for k := range extra {
+		if k == "" {
+			return fmt.Errorf("invalid extra_body: empty field name")
+		}
+		for _, r := range reservedFields {
+			if k == r {
+				return fmt.Errorf("invalid extra_body: field %q can not be overridden", k)
+			}
+		}
+	}
+	if _, err := json.Marshal(extra); err != nil {
+		return fmt.Errorf("invalid extra_body: %w", err)
+	}
+	return nil
+}
+
+type transport struct {
+	extra map[string]any
+	base  http.RoundTripper
+}
+
+// NewTransport 返回合并扩展字段的 RoundTripper, 只处理请求体为 JSON 对象的 POST 请求.
+// 需要签名的客户端(如 SigV4)应将签名 Transport 作为 base, 保证签名覆盖合并后的请求体
+func NewTransport(extra map[string]any, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{extra: extra, base: base}
+}
+
+// WrapClient 返回使用 NewTransport 的客户端副本, c 为空时使用默认客户端
+func WrapClient(c *http.Client, extra map[string]any) *http.Client {
+	nc := &http.Client{}
+	if c != nil {
+		*nc = *c
+	}
+	nc.Transport = NewTransport(extra, nc.Transport)
+	return nc
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	extra := t.extra
+	if override := FromContext(req.Context()); len(override) > 0 {
+		extra = overlay(t.extra, override)
+	}
+	if len(extra) == 0 || req.Body == nil || req.Method != http.MethodPost {
+		return t.base.RoundTrip(req)
+	}
+	raw, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	body := apply(raw, extra)
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return t.base.RoundTrip(out)
+}
+
+// apply 请求体不是 JSON 对象时原样返回
+func apply(raw []byte, extra map[string]any) []byte {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var body map[string]any
+	if err := dec.Decode(&body); err != nil || body == nil {
+		return raw
+	}
+	Merge(body, extra)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(body); err != nil {
+		return raw
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
@@ -0,0 +1,72 @@
+package extrabody
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// captureTransport 记录实际发出的请求体
+type captureTransport struct {
+	body map[string]any
+}
+
+func (c *captureTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.body = nil
+	_ = json.NewDecoder(r.Body).Decode(&c.body)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: r}, nil
+}
+
+func TestTransport_Merge(t *testing.T) {
+	cases := []struct {
+		name     string
+		model    map[string]any
+		requests []map[string]any
+		want     map[string]any
+	}{
+		{
+			name:  "model only",
+			model: map[string]any{"temperature": nil, "enable_search": true},
+			want:  map[string]any{"model": "m", "top_p": 0.9, "options": map[string]any{"num_ctx": 2048.0}, "enable_search": true},
+		},
+		{
+			name:     "model null with request override",
+			model:    map[string]any{"temperature": nil, "options": map[string]any{"num_ctx": nil}},
+			requests: []map[string]any{{"enable_search": true}},
+			want:     map[string]any{"model": "m", "top_p": 0.9, "options": map[string]any{}, "enable_search": true},
+		},
+		{
+			name:     "request null across WithContext calls",
+			requests: []map[string]any{{"top_p": nil}, {"enable_search": true}},
+			want:     map[string]any{"model": "m", "temperature": 0.7, "options": map[string]any{"num_ctx": 2048.0}, "enable_search": true},
+		},
+		{
+			name:     "request value replaces model null",
+			model:    map[string]any{"temperature": nil},
+			requests: []map[string]any{{"temperature": 0.2}},
+			want:     map[string]any{"model": "m", "temperature": 0.2, "top_p": 0.9, "options": map[string]any{"num_ctx": 2048.0}},
+		},
+	}
+	for _, tc := range cases {
+		base := &captureTransport{}
+		client := &http.Client{Transport: NewTransport(tc.model, base)}
+		ctx := context.Background()
+		for _, extra := range tc.requests {
+			ctx = WithContext(ctx, extra)
+		}
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "http://example.com/chat",
+			strings.NewReader(`{"model":"m","temperature":0.7,"top_p":0.9,"options":{"num_ctx":2048}}`))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tc.name, err)
+		}
+		_ = resp.Body.Close()
+		if !reflect.DeepEqual(base.body, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, base.body, tc.want)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cloudwego/eino-ext/libs/acl/openai"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+// defaultMaxRepairs 校验失败后默认最多追加的修复轮数
+const defaultMaxRepairs = 2
+
+// Error 多轮修复后输出仍不符合 Schema
+type Error struct {
+	// 总请求次数, 含首次请求
+	Attempts int
+	// 最后一次输出的原始内容
+	Content string
+	// 最后一次输出的问题, 格式为 "$.path: 原因"
+	Issues []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("extract: output does not match schema after %d attempts: %s", e.Attempts, strings.Join(e.Issues, "; "))
+}
+
+type options struct {
+	maxRepairs   int
+	name         string
+	modelOptions []model.Option
+}
+
+type Option func(*options)
+
+func newOptions(opts []Option) *options {
+	o := &options{maxRepairs: defaultMaxRepairs}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// schemaName WithName 未设置时使用结构体名称
+func (o *options) schemaName(t reflect.Type) string {
+	if o.name != "" {
+		return o.name
+	}
+	return schemaName(t)
+}
+
+// WithMaxRepairs 校验失败后最多追加的修复轮数, 0 表示不修复
+func WithMaxRepairs(n int) Option {
+	return func(o *options) {
+		if n >= 0 {
+			o.maxRepairs = n
+		}
+	}
+}
+
+// WithName Schema 名称, 默认为结构体名称
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithModelOptions 透传给每一次 Generate 调用的选项
+func WithModelOptions(opts ...model.Option) Option {
+	return func(o *options) {
+		o.modelOptions = append(o.modelOptions, opts...)
+	}
+}
+
+// Schema 根据结构体生成内联的 JSON Schema, 字段名取 json 标签, 未标记 omitempty 的字段为必填,
+// 描述、枚举、取值范围等约束可通过 jsonschema 标签设置, 例如 `jsonschema:"description=姓名,enum=a,enum=b"`.
+// Schema 全部内联展开, 不支持自引用的类型
+func Schema[T any]() (*jsonschema.Schema, error) {
+	t := reflect.TypeFor[T]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("extract: %s is not a struct", t)
+	}
+	// 内联展开时 Reflector 遇到递归类型会无限递归
+	if c := findCycle(t, map[reflect.Type]bool{}); c != nil {
+		return nil, fmt.Errorf("extract: %s is recursive and cannot be inlined", c)
+	}
+	r := &jsonschema.Reflector{
+		Anonymous:      true,
+		DoNotReference: true,
+		ExpandedStruct: true,
+	}
+	s := r.ReflectFromType(t)
+	s.Version = ""
+	return s, nil
+}
+
+// ResponseFormat 用于 ModelMetadata.ResponseFormat, 使各提供商按同一 Schema 约束输出
+func ResponseFormat[T any]() (*openai.ChatCompletionResponseFormat, error) {
+	s, err := Schema[T]()
+	if err != nil {
+		return nil, err
+	}
+	return responseFormat(s, schemaName(reflect.TypeFor[T]())), nil
+}
+
+func responseFormat(s *jsonschema.Schema, name string) *openai.ChatCompletionResponseFormat {
+	return &openai.ChatCompletionResponseFormat{
+		Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+		JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+			Name:       name,
+			JSONSchema: s,
+		},
+	}
+}
+
+// ChatModelFactory 按 ModelMetadata 构建对话模型, 例如 (*usecase.ModelKit).GetChatModel
+type ChatModelFactory func(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error)
+
+// ExtractWithMetadata 在 md 的副本上按 T 设置 ResponseFormat 后构建对话模型, 各提供商按 Schema 约束输出,
+// 再按 Extract 的方式校验与修复. md 中已有的 ResponseFormat 会被替换
+func ExtractWithMetadata[T any](ctx context.Context, newChatModel ChatModelFactory, md *domain.ModelMetadata, messages []*schema.Message, opts ...Option) (T, error) {
+	var out T
+	if newChatModel == nil || md == nil {
+		return out, errors.New("extract: chat model factory and metadata are required")
+	}
+	s, err := Schema[T]()
+	if err != nil {
+		return out, err
+	}
+	withFormat := *md
+	withFormat.ResponseFormat = responseFormat(s, newOptions(opts).schemaName(reflect.TypeFor[T]()))
+	chatModel, err := newChatModel(ctx, &withFormat)
+	if err != nil {
+		return out, err
+	}
+	return Extract[T](ctx, chatModel, messages, opts...)
+}
+
+// Extract 通过 system 提示要求模型按 T 的 Schema 输出 JSON 并校验, 不符合时带上校验错误重新请求,
+// 超过修复轮数返回 *Error. Extract 不修改 chatModel 的配置, 需要提供商按 Schema 约束输出时
+// 使用 ExtractWithMetadata, 或在构建 chatModel 时设置 ResponseFormat
+func Extract[T any](ctx context.Context, chatModel model.BaseChatModel, messages []*schema.Message, opts ...Option) (T, error) {
+	var out T
+	if chatModel == nil {
+		return out, errors.New("extract: chat model is nil")
+	}
+	o := newOptions(opts)
+	s, err := Schema[T]()
+	if err != nil {
+		return out, err
+	}
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return out, fmt.Errorf("extract: marshal schema failed: %w", err)
+	}
+	var generic map[string]any
+	if err := decode(raw, &generic); err != nil {
+		return out, fmt.Errorf("extract: decode schema failed: %w", err)
+	}
+	name := o.schemaName(reflect.TypeFor[T]())
+
+	msgs := make([]*schema.Message, 0, len(messages)+1+2*o.maxRepairs)
+	msgs = append(msgs, schema.SystemMessage(fmt.Sprintf(
+		"Extract the requested information as a JSON object named %q that conforms to the following JSON Schema. Respond with the JSON object only, without markdown or any other text.\n%s",
+		name, raw)))
+	msgs = append(msgs, messages...)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := chatModel.Generate(ctx, msgs, o.modelOptions...)
+		if err != nil {
+			return out, err
+		}
+		content := ""
+		if resp != nil {
+			content = resp.Content
+		}
+		issues := check(content, generic, &out)
+		if len(issues) == 0 {
+			return out, nil
+		}
+		if attempt > o.maxRepairs {
+			return out, &Error{Attempts: attempt, Content: content, Issues: issues}
+		}
+		out = *new(T)
+		msgs = append(msgs,
+			schema.AssistantMessage(content, nil),
+			schema.UserMessage("The previous reply does not conform to the JSON Schema:\n- "+strings.Join(issues, "\n- ")+
+				"\nFix these problems and respond with the corrected JSON object only."),
+		)
+	}
+}
+
+// check 解析并校验模型输出, 通过后写入 out
+func check(content string, schemaMap map[string]any, out any) []string {
+	body := trimJSON(content)
+	if body == "" {
+		return []string{"$: no JSON object found in the reply"}
+	}
+	var v any
+	if err := decode([]byte(body), &v); err != nil {
+		return []string{"$: invalid JSON: " + err.Error()}
+	}
+	if issues := validate(schemaMap, v); len(issues) > 0 {
+		return issues
+	}
+	if err := json.Unmarshal([]byte(body), out); err != nil {
+		return []string{"$: " + err.Error()}
+	}
+	return nil
+}
+
+// trimJSON 去掉 markdown 代码块及 JSON 对象前后的说明文字
+func trimJSON(content string) string {
+	s := strings.TrimSpace(content)
+	if rest, ok := strings.CutPrefix(s, "```"); ok {
+		if i := strings.IndexByte(rest, '\n'); i >= 0 {
+			rest = rest[i+1:]
+		}
+		s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest), "```"))
+	}
+	start, end := strings.IndexByte(s, '{'), strings.LastIndexByte(s, '}')
+	if start < 0 || end < start {
+		return ""
+	}
+	return s[start : end+1]
+}
+
+// findCycle 返回 t 的字段中引用自身或外层结构体的类型, 无递归时返回 nil
+func findCycle(t reflect.Type, visiting map[reflect.Type]bool) reflect.Type {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return findCycle(t.Elem(), visiting)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	if visiting[t] {
+		return t
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if (!f.IsExported() && !f.Anonymous) || f.Tag.Get("json") == "-" {
+			continue
+		}
+		if c := findCycle(f.Type, visiting); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+func decode(b []byte, v any) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+func schemaName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return "response"
+	}
+	return t.Name()
+}
@@ -0,0 +1,100 @@
+package extract
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/libs/acl/openai"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+type testInvoice struct {
+	Number   string  `json:"number" jsonschema:"description=发票号码"`
+	Currency string  `json:"currency" jsonschema:"enum=CNY,enum=USD"`
+	Amount   float64 `json:"amount" jsonschema:"minimum=0"`
+	Items    []struct {
+		Name     string `json:"name"`
+		Quantity int    `json:"quantity"`
+	} `json:"items"`
+	Note string `json:"note,omitempty"`
+}
+
+// fakeChatModel 依次返回 replies 中的内容, 并记录每次请求的消息
+type fakeChatModel struct {
+	replies  []string
+	requests [][]*schema.Message
+}
+
+func (f *fakeChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	f.requests = append(f.requests, input)
+	return schema.AssistantMessage(f.replies[min(len(f.requests), len(f.replies))-1], nil), nil
+}
+
+func (f *fakeChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestExtract_Repair(t *testing.T) {
+	cm := &fakeChatModel{replies: []string{
+		`{"number":"INV-1","currency":"EUR","amount":-1,"items":[{"name":"pen"}],"extra":true}`,
+		"```json\n{\"number\":\"INV-1\",\"currency\":\"CNY\",\"amount\":12.5,\"items\":[{\"name\":\"pen\",\"quantity\":5}]}\n```",
+	}}
+	invoice, err := Extract[testInvoice](context.Background(), cm, []*schema.Message{schema.UserMessage("发票 INV-1, 5 支笔共 12.5 元")})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if invoice.Number != "INV-1" || invoice.Currency != "CNY" || invoice.Amount != 12.5 || len(invoice.Items) != 1 || invoice.Items[0].Quantity != 5 {
+		t.Fatalf("unexpected invoice: %+v", invoice)
+	}
+
+	if len(cm.requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(cm.requests))
+	}
+	if system := cm.requests[0][0].Content; !strings.Contains(system, `"required":["number","currency","amount","items"]`) {
+		t.Fatalf("schema not in system prompt: %s", system)
+	}
+	repair := cm.requests[1][len(cm.requests[1])-1].Content
+	for _, want := range []string{`$.currency: must be one of ["CNY","USD"]`, "$.amount: must be >= 0", `$.items[0]: missing required property "quantity"`, `$: unexpected property "extra"`} {
+		if !strings.Contains(repair, want) {
+			t.Errorf("repair message missing %q:\n%s", want, repair)
+		}
+	}
+}
+
+func TestExtract_Exhausted(t *testing.T) {
+	cm := &fakeChatModel{replies: []string{"抱歉, 无法识别"}}
+	_, err := Extract[testInvoice](context.Background(), cm, []*schema.Message{schema.UserMessage("hi")}, WithMaxRepairs(1))
+	var extractErr *Error
+	if !errors.As(err, &extractErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if extractErr.Attempts != 2 || len(cm.requests) != 2 || extractErr.Content != "抱歉, 无法识别" || len(extractErr.Issues) != 1 {
+		t.Fatalf("unexpected error: %+v", extractErr)
+	}
+}
+
+func TestExtractWithMetadata(t *testing.T) {
+	md := &domain.ModelMetadata{ModelName: "gpt-4o-mini"}
+	var built *domain.ModelMetadata
+	cm := &fakeChatModel{replies: []string{`{"number":"INV-1","currency":"CNY","amount":1,"items":[]}`}}
+	factory := func(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
+		built = md
+		return cm, nil
+	}
+	invoice, err := ExtractWithMetadata[testInvoice](context.Background(), factory, md, []*schema.Message{schema.UserMessage("hi")}, WithName("invoice"))
+	if err != nil || invoice.Number != "INV-1" {
+		t.Fatalf("ExtractWithMetadata failed: %v %+v", err, invoice)
+	}
+	rf := built.ResponseFormat
+	if rf == nil || rf.Type != openai.ChatCompletionResponseFormatTypeJSONSchema || rf.JSONSchema.Name != "invoice" || rf.JSONSchema.JSONSchema == nil {
+		t.Fatalf("response format not requested: %+v", rf)
+	}
+	if built.ModelName != md.ModelName || md.ResponseFormat != nil {
+		t.Fatalf("metadata should be copied: %+v", md)
+	}
+}
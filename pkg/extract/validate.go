@@ -0,0 +1,294 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// validate 按 JSON Schema 校验 v, 返回所有不满足的位置及原因.
+// schema 与 v 均为 json.Decoder(UseNumber) 解码后的通用结构,
+// 只支持 Reflector 生成的关键字, 不支持 $ref
+func validate(schema map[string]any, v any) []string {
+	var issues []string
+	validateAt("$", schema, v, &issues)
+	return issues
+}
+
+func validateAt(path string, schema map[string]any, v any, issues *[]string) {
+	addf := func(format string, args ...any) {
+		*issues = append(*issues, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool { return matchType(t, v) }) {
+		addf("expected %s, got %s", strings.Join(types, " or "), typeName(v))
+		return
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return jsonEqual(e, v) }) {
+		addf("must be one of %s", compact(enum))
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(c, v) {
+		addf("must be %s", compact(c))
+	}
+	for _, sub := range schemaList(schema["allOf"]) {
+		validateAt(path, sub, v, issues)
+	}
+	if anyOf := schemaList(schema["anyOf"]); len(anyOf) > 0 && countMatches(anyOf, v) == 0 {
+		addf("does not match any of the allowed schemas")
+	}
+	if oneOf := schemaList(schema["oneOf"]); len(oneOf) > 0 && countMatches(oneOf, v) != 1 {
+		addf("must match exactly one of the allowed schemas")
+	}
+
+	switch x := v.(type) {
+	case map[string]any:
+		validateObject(path, schema, x, issues, addf)
+	case []any:
+		validateArray(path, schema, x, issues, addf)
+	case string:
+		n := uint64(utf8.RuneCountInString(x))
+		if limit, ok := schemaUint(schema["minLength"]); ok && n < limit {
+			addf("length must be at least %d", limit)
+		}
+		if limit, ok := schemaUint(schema["maxLength"]); ok && n > limit {
+			addf("length must be at most %d", limit)
+		}
+		if p, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(p); err == nil && !re.MatchString(x) {
+				addf("must match pattern %q", p)
+			}
+		}
+	case json.Number:
+		f, err := x.Float64()
+		if err != nil {
+			return
+		}
+		if limit, ok := schemaFloat(schema["minimum"]); ok && f < limit {
+			addf("must be >= %v", limit)
+		}
+		if limit, ok := schemaFloat(schema["maximum"]); ok && f > limit {
+			addf("must be <= %v", limit)
+		}
+		if limit, ok := schemaFloat(schema["exclusiveMinimum"]); ok && f <= limit {
+			addf("must be > %v", limit)
+		}
+		if limit, ok := schemaFloat(schema["exclusiveMaximum"]); ok && f >= limit {
+			addf("must be < %v", limit)
+		}
+	}
+}
+
+func validateObject(path string, schema, obj map[string]any, issues *[]string, addf func(string, ...any)) {
+	for _, name := range schemaStrings(schema["required"]) {
+		if _, ok := obj[name]; !ok {
+			addf("missing required property %q", name)
+		}
+	}
+	props, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if sub, ok := props[k].(map[string]any); ok {
+			validateAt(path+"."+k, sub, obj[k], issues)
+			continue
+		}
+		switch ap := schema["additionalProperties"].(type) {
+		case bool:
+			if !ap {
+				addf("unexpected property %q", k)
+			}
+		case map[string]any:
+			validateAt(path+"."+k, ap, obj[k], issues)
+		}
+	}
+	if limit, ok := schemaUint(schema["minProperties"]); ok && uint64(len(obj)) < limit {
+		addf("must have at least %d properties", limit)
+	}
+	if limit, ok := schemaUint(schema["maxProperties"]); ok && uint64(len(obj)) > limit {
+		addf("must have at most %d properties", limit)
+	}
+}
+
+func validateArray(path string, schema map[string]any, arr []any, issues *[]string, addf func(string, ...any)) {
+	if limit, ok := schemaUint(schema["minItems"]); ok && uint64(len(arr)) < limit {
+		addf("must have at least %d items", limit)
+	}
+	if limit, ok := schemaUint(schema["maxItems"]); ok && uint64(len(arr)) > limit {
+		addf("must have at most %d items", limit)
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range arr {
+			validateAt(fmt.Sprintf("%s[%d]", path, i), items, item, issues)
+		}
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if jsonEqual(arr[i], arr[j]) {
+					addf("items %d and %d are duplicated", i, j)
+				}
+			}
+		}
+	}
+}
+
+func countMatches(schemas []map[string]any, v any) int {
+	n := 0
+	for _, s := range schemas {
+		if len(validate(s, v)) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func matchType(t string, v any) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		if _, err := n.Int64(); err == nil {
+			return true
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	}
+	return true
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func schemaTypes(v any) []string {
+	if s, ok := v.(string); ok {
+		return []string{s}
+	}
+	return schemaStrings(v)
+}
+
+func schemaStrings(v any) []string {
+	list, _ := v.([]any)
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func schemaList(v any) []map[string]any {
+	list, _ := v.([]any)
+	out := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if m, ok := item.(map[string]any); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func schemaFloat(v any) (float64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+func schemaUint(v any) (uint64, bool) {
+	f, ok := schemaFloat(v)
+	if !ok || f < 0 {
+		return 0, false
+	}
+	return uint64(f), true
+}
+
+// jsonEqual 数字按数值比较, 其余按结构比较
+func jsonEqual(a, b any) bool {
+	if na, ok := a.(json.Number); ok {
+		nb, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		fa, errA := na.Float64()
+		fb, errB := nb.Float64()
+		return errA == nil && errB == nil && fa == fb
+	}
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compact(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,92 @@
+package extract
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		value  string
+		issues []string
+	}{
+		{"enum ok", `{"enum":["CNY","USD"]}`, `"CNY"`, nil},
+		{"enum mismatch", `{"enum":["CNY","USD"]}`, `"EUR"`, []string{`$: must be one of ["CNY","USD"]`}},
+		{"enum number", `{"enum":[1,2]}`, `2.0`, nil},
+		{"const", `{"const":"a"}`, `"b"`, []string{`$: must be "a"`}},
+
+		{"anyOf ok", `{"anyOf":[{"type":"string"},{"type":"integer"}]}`, `3`, nil},
+		{"anyOf mismatch", `{"anyOf":[{"type":"string"},{"type":"integer"}]}`, `true`, []string{"$: does not match any of the allowed schemas"}},
+		{"oneOf ok", `{"oneOf":[{"type":"string"},{"type":"integer"}]}`, `"x"`, nil},
+		{"oneOf none", `{"oneOf":[{"type":"string"},{"type":"integer"}]}`, `1.5`, []string{"$: must match exactly one of the allowed schemas"}},
+		{"oneOf both", `{"oneOf":[{"type":"number"},{"type":"integer"}]}`, `1`, []string{"$: must match exactly one of the allowed schemas"}},
+
+		{"integer ok", `{"type":"integer"}`, `42`, nil},
+		{"integer float form", `{"type":"integer"}`, `42.0`, nil},
+		{"integer fraction", `{"type":"integer"}`, `4.2`, []string{"$: expected integer, got number"}},
+		{"integer string", `{"type":"integer"}`, `"42"`, []string{"$: expected integer, got string"}},
+		{"number fraction", `{"type":"number"}`, `4.2`, nil},
+		{"number or null", `{"type":["number","null"]}`, `null`, nil},
+
+		{"additionalProperties false", `{"type":"object","properties":{"a":{}},"additionalProperties":false}`, `{"a":1,"b":2}`, []string{`$: unexpected property "b"`}},
+		{"additionalProperties schema", `{"type":"object","additionalProperties":{"type":"string"}}`, `{"a":"x","b":2}`, []string{"$.b: expected string, got number"}},
+		{"additionalProperties default", `{"type":"object","properties":{"a":{}}}`, `{"a":1,"b":2}`, nil},
+		{"required", `{"type":"object","required":["a","b"]}`, `{"a":1}`, []string{`$: missing required property "b"`}},
+
+		{"uniqueItems ok", `{"type":"array","uniqueItems":true}`, `[1,"1",{"a":1},{"a":2}]`, nil},
+		{"uniqueItems numbers", `{"type":"array","uniqueItems":true}`, `[1,2,1.0]`, []string{"$: items 0 and 2 are duplicated"}},
+		{"uniqueItems objects", `{"type":"array","uniqueItems":true}`, `[{"a":[1]},{"a":[1]}]`, []string{"$: items 0 and 1 are duplicated"}},
+		{"items", `{"type":"array","items":{"type":"string"},"maxItems":1}`, `["a",1]`, []string{"$: must have at most 1 items", "$[1]: expected string, got number"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema map[string]any
+			if err := decode([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("decode schema failed: %v", err)
+			}
+			var v any
+			if err := decode([]byte(tt.value), &v); err != nil {
+				t.Fatalf("decode value failed: %v", err)
+			}
+			if got := validate(schema, v); !reflect.DeepEqual(got, tt.issues) {
+				t.Fatalf("validate() = %q, want %q", got, tt.issues)
+			}
+		})
+	}
+}
+
+type testNode struct {
+	Name     string     `json:"name"`
+	Children []testNode `json:"children"`
+}
+
+type testTree struct {
+	Root *struct {
+		Parent *testTree `json:"parent"`
+	} `json:"root"`
+}
+
+type testFlat struct {
+	A []struct {
+		Name string `json:"name"`
+	} `json:"a"`
+	B []struct {
+		Name string `json:"name"`
+	} `json:"b"`
+	Skip *testFlat `json:"-"`
+}
+
+func TestSchema_Recursive(t *testing.T) {
+	if _, err := Schema[testNode](); err == nil || !strings.Contains(err.Error(), "recursive") {
+		t.Fatalf("expected recursive error, got %v", err)
+	}
+	if _, err := Schema[testTree](); err == nil || !strings.Contains(err.Error(), "recursive") {
+		t.Fatalf("expected recursive error, got %v", err)
+	}
+	if _, err := Schema[testFlat](); err != nil {
+		t.Fatalf("Schema failed: %v", err)
+	}
+}
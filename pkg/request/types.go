@@ -1,5 +1,10 @@
 package request
 
+import (
+	"errors"
+	"fmt"
+)
+
 type Ctx struct {
 	body        any
 	header      Header
@@ -15,3 +20,21 @@ type Response[T any] struct {
 
 type Query map[string]string
 type Header map[string]string
+
+// StatusError 响应状态码不是 200
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status code: %d", e.StatusCode)
+}
+
+// StatusCode 返回 err 中的响应状态码, 不是 StatusError 时返回 0
+func StatusCode(err error) int {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,206 @@
+package sigv4
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	algorithm   = "AWS4-HMAC-SHA256"
+	timeFormat  = "20060102T150405Z"
+	dateFormat  = "20060102"
+	headerDate  = "X-Amz-Date"
+	headerToken = "X-Amz-Security-Token"
+)
+
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+type Signer struct {
+	Credentials Credentials
+	Region      string
+	Service     string
+}
+
+func NewSigner(creds Credentials, region, service string) (*Signer, error) {
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, errors.New("missing aws access key id or secret access key")
+	}
+	if region == "" || service == "" {
+		return nil, errors.New("missing aws region or service")
+	}
+	return &Signer{Credentials: creds, Region: region, Service: service}, nil
+}
+
+// Sign 对请求进行 SigV4 签名, body 为请求体原文
+func (s *Signer) Sign(req *http.Request, body []byte, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format(timeFormat)
+	payloadHash := hashHex(body)
+
+	req.Header.Set(headerDate, amzDate)
+	if s.Credentials.SessionToken != "" {
+		req.Header.Set(headerToken, s.Credentials.SessionToken)
+	}
+
+	signedHeaders, canonicalHeaders := canonicalHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		CanonicalURI(req.URL.EscapedPath()),
+		canonicalQuery(req),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{t.Format(dateFormat), s.Region, s.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{algorithm, amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.Credentials.SecretAccessKey), t.Format(dateFormat))
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, s.Credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// CanonicalURI 将请求行中已编码的路径(URL.EscapedPath)再编码一次, 即 S3 以外服务要求的两次编码
+func CanonicalURI(escapedPath string) string {
+	if escapedPath == "" {
+		return "/"
+	}
+	segments := strings.Split(escapedPath, "/")
+	for i, seg := range segments {
+		segments[i] = uriEncode(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// EscapePathSegment 按 SigV4 规则编码单段路径, 除非保留字符外全部转义(包括 ':').
+// 拼接到 URL 后 url.Parse 会保留 RawPath, 请求行与签名使用同一编码
+func EscapePathSegment(seg string) string {
+	return uriEncode(seg)
+}
+
+func canonicalQuery(req *http.Request) string {
+	values := req.URL.Query()
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		vs := values[k]
+		sort.Strings(vs)
+		for _, v := range vs {
+			pairs = append(pairs, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func canonicalHeaders(req *http.Request) (string, string) {
+	headers := map[string]string{"host": req.Host}
+	if req.Host == "" {
+		headers["host"] = req.URL.Host
+	}
+	for k, vs := range req.Header {
+		lk := strings.ToLower(k)
+		if lk == "content-type" || strings.HasPrefix(lk, "x-amz-") {
+			trimmed := make([]string, 0, len(vs))
+			for _, v := range vs {
+				trimmed = append(trimmed, strings.Join(strings.Fields(v), " "))
+			}
+			headers[lk] = strings.Join(trimmed, ",")
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, k := range names {
+		b.WriteString(k)
+		b.WriteString(":")
+		b.WriteString(headers[k])
+		b.WriteString("\n")
+	}
+	return strings.Join(names, ";"), b.String()
+}
+
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func hashHex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+type transport struct {
+	signer *Signer
+	base   http.RoundTripper
+	now    func() time.Time
+}
+
+// NewTransport 返回在发送前对请求进行 SigV4 签名的 RoundTripper
+func NewTransport(signer *Signer, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{signer: signer, base: base, now: time.Now}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+	// RoundTripper 不应修改原始请求
+	signed := req.Clone(req.Context())
+	signed.Header.Del("Authorization")
+	if body != nil {
+		signed.Body = io.NopCloser(bytes.NewReader(body))
+		signed.ContentLength = int64(len(body))
+	}
+	t.signer.Sign(signed, body, t.now())
+	return t.base.RoundTrip(signed)
+}
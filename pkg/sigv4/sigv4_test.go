@@ -0,0 +1,106 @@
+package sigv4
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 官方 aws-sig-v4-test-suite 用例, 请求行中的路径均为已编码的形式
+func TestSign_TestSuite(t *testing.T) {
+	signer, err := NewSigner(Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "service")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	signedAt := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		contentType string
+		body        string
+		signed      string
+		signature   string
+	}{
+		{"get-vanilla", http.MethodGet, "https://example.amazonaws.com/", "", "", "host;x-amz-date", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"get-vanilla-empty-query-key", http.MethodGet, "https://example.amazonaws.com/?Param1=value1", "", "", "host;x-amz-date", "a67d582fa61cc504c4bae71f336f98b97f1ea3c7a6bfe1b6e45aec72011b9aeb"},
+		{"get-vanilla-query-order-key-case", http.MethodGet, "https://example.amazonaws.com/?Param2=value2&Param1=value1", "", "", "host;x-amz-date", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+		{"get-unreserved", http.MethodGet, "https://example.amazonaws.com/-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz", "", "", "host;x-amz-date", "07ef7494c76fa4850883e2b006601f940f8a34d404d0cfa977f52a65bbf5f24f"},
+		{"post-vanilla", http.MethodPost, "https://example.amazonaws.com/", "", "", "host;x-amz-date", "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b"},
+		{"post-x-www-form-urlencoded", http.MethodPost, "https://example.amazonaws.com/", "application/x-www-form-urlencoded", "Param1=value1", "content-type;host;x-amz-date", "ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("NewRequest failed: %v", err)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			signer.Sign(req, []byte(tt.body), signedAt)
+			want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=" +
+				tt.signed + ", Signature=" + tt.signature
+			if got := req.Header.Get("Authorization"); got != want {
+				t.Fatalf("unexpected Authorization:\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestCanonicalURI(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"/model/anthropic.claude-3-haiku-20240307-v1%3A0/converse", "/model/anthropic.claude-3-haiku-20240307-v1%253A0/converse"},
+		{"/example%20space/", "/example%2520space/"},
+		{"/%E1%88%B4", "/%25E1%2588%25B4"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalURI(tt.path); got != tt.want {
+			t.Errorf("CanonicalURI(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// 路径按 SigV4 规则编码后, 请求行中的 ':' 为 %3A, 签名使用再编码一次的 %253A
+func TestTransport_EscapedPath(t *testing.T) {
+	signer, err := NewSigner(Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, "us-east-1", "bedrock")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	var sent *http.Request
+	rt := NewTransport(signer, roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}))
+	endpoint := "https://bedrock-runtime.us-east-1.amazonaws.com/model/" + EscapePathSegment("anthropic.claude-3-haiku-20240307-v1:0") + "/converse"
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip failed: %v", err)
+	}
+	if got := sent.URL.RequestURI(); got != "/model/anthropic.claude-3-haiku-20240307-v1%3A0/converse" {
+		t.Fatalf("unexpected request uri: %s", got)
+	}
+	if sent.Header.Get("Authorization") == "" {
+		t.Fatal("missing Authorization")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
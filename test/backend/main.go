@@ -77,6 +77,15 @@ func (p *ModelKitHandler) CheckModel(c echo.Context) error {
 	req.APIHeader = c.QueryParam("api_header")
 	req.APIVersion = c.QueryParam("api_version")
 	req.Type = c.QueryParam("model_type")
+	req.AWSAccessKeyID = c.QueryParam("aws_access_key_id")
+	req.AWSSecretAccessKey = c.QueryParam("aws_secret_access_key")
+	req.AWSSessionToken = c.QueryParam("aws_session_token")
+	req.AWSRegion = c.QueryParam("aws_region")
+	req.VertexProject = c.QueryParam("vertex_project")
+	req.VertexLocation = c.QueryParam("vertex_location")
+	req.VertexServiceAccount = c.QueryParam("vertex_service_account")
+	req.RerankProtocol = c.QueryParam("rerank_protocol")
+	req.ChatAPI = c.QueryParam("chat_api")
 
 	p.logger.Info("CheckModel req", slog.Any("req", req))
 
@@ -96,6 +105,15 @@ func (p *ModelKitHandler) CheckModel(c echo.Context) error {
 		}
 	}
 
+	if extraBodyStr := c.QueryParam("extra_body"); extraBodyStr != "" {
+		if err := json.Unmarshal([]byte(extraBodyStr), &req.ExtraBody); err != nil {
+			return c.JSON(http.StatusBadRequest, domain.Response{
+				Success: false,
+				Message: "extra_body 参数解析失败: " + err.Error(),
+			})
+		}
+	}
+
 	// 验证必需参数
 	if req.Provider == "" {
 		return c.JSON(http.StatusBadRequest, domain.Response{
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+func TestCheckModel_ArkMultimodalEmbedding(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/embeddings/multimodal" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		var body struct {
+			Model string `json:"model"`
+			Input []struct {
+				Type     string `json:"type"`
+				ImageURL *struct {
+					URL string `json:"url"`
+				} `json:"image_url"`
+			} `json:"input"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Input) != 2 || body.Input[1].Type != "image_url" || !strings.HasPrefix(body.Input[1].ImageURL.URL, "data:image/") {
+			t.Errorf("unexpected request: %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":{"object":"embedding","embedding":[0.1,0.2,0.3]},"usage":{"prompt_tokens":530,"total_tokens":530}}`))
+	}))
+	defer ts.Close()
+
+	mk := NewModelKit(nil)
+	resp, err := mk.CheckModel(context.Background(), &domain.CheckModelReq{
+		Provider: string(consts.ModelProviderVolcengine),
+		Model:    "doubao-embedding-vision-250615",
+		BaseURL:  ts.URL + "/api/v3",
+		APIKey:   "ark-key",
+		Type:     string(consts.ModelTypeEmbedding),
+		Param:    &domain.ModelParam{SupportImages: true},
+	})
+	if err != nil {
+		t.Fatalf("CheckModel failed: %v", err)
+	}
+	if resp.Error != "" || resp.Content != "dim is : 3" {
+		t.Fatalf("unexpected check result: %+v", resp)
+	}
+}
+
+func TestCheckModel_ImageEmbeddingUnsupported(t *testing.T) {
+	mk := NewModelKit(nil)
+	resp, err := mk.CheckModel(context.Background(), &domain.CheckModelReq{
+		Provider: string(consts.ModelProviderVoyageAI),
+		Model:    "voyage-3.5",
+		BaseURL:  "http://127.0.0.1:1/v1",
+		APIKey:   "pa-test",
+		Type:     string(consts.ModelTypeEmbedding),
+		Param:    &domain.ModelParam{SupportImages: true},
+	})
+	if err != nil {
+		t.Fatalf("CheckModel failed: %v", err)
+	}
+	if resp.Error != "this model not support image input" {
+		t.Fatalf("unexpected check result: %+v", resp)
+	}
+}
+
+func TestGetReranker_ArkKnowledge(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/knowledge/service/rerank" || r.Header.Get("Authorization") != "Bearer kb-key" {
+			t.Errorf("unexpected request: %s %v", r.URL.Path, r.Header)
+		}
+		var body struct {
+			Datas []struct {
+				Query   string `json:"query"`
+				Content string `json:"content"`
+			} `json:"datas"`
+			RerankModel string `json:"rerank_model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Datas) != 3 || body.Datas[2].Query != "动物" || body.RerankModel != "m3-v2-rerank" {
+			t.Errorf("unexpected request: %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":0,"message":"success","data":{"scores":[0.1,0.3,0.9],"token_usage":15}}`))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	rk, err := mk.GetReranker(ctx, &domain.ModelMetadata{
+		Provider:  consts.ModelProviderVolcengine,
+		ModelName: "m3-v2-rerank",
+		BaseURL:   "https://ark.cn-beijing.volces.com/api/v3",
+		APIKey:    "ark-key",
+
+		VolcengineKnowledgeBaseURL: ts.URL,
+		VolcengineKnowledgeAPIKey:  "kb-key",
+	})
+	if err != nil {
+		t.Fatalf("GetReranker failed: %v", err)
+	}
+	n := 2
+	resp, err := rk.Rerank(ctx, domain.RerankRequest{
+		Query:           "动物",
+		Documents:       []string{"火", "面", "火鸡"},
+		ReturnDocuments: true,
+		N:               &n,
+	})
+	if err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Document != "火鸡" || resp.Results[1].Index != 1 || resp.Usage.TotalTokens != 15 {
+		t.Fatalf("unexpected rerank result: %+v", resp)
+	}
+}
+
+func TestModelList_VolcengineEndpoints(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v3/models":
+			if r.Header.Get("Authorization") != "Bearer ark-key" {
+				t.Errorf("unexpected authorization: %v", r.Header)
+			}
+			_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"deepseek-v3-250324","object":"model"}]}`))
+		case r.URL.Query().Get("Action") == "ListEndpoints":
+			if r.URL.Query().Get("Version") != "2024-01-01" || !strings.HasPrefix(r.Header.Get("Authorization"), "HMAC-SHA256 Credential=AKLT-test/") {
+				t.Errorf("unexpected ListEndpoints request: %s %v", r.URL, r.Header)
+			}
+			_, _ = w.Write([]byte(`{"ResponseMetadata":{"RequestId":"1","Action":"ListEndpoints","Version":"2024-01-01","Service":"ark","Region":"cn-beijing"},
+				"Result":{"TotalCount":3,"PageNumber":1,"PageSize":100,"Items":[
+				{"Id":"ep-20250601-chat","Name":"chat","Status":"Running","ModelReference":{"FoundationModel":{"Name":"doubao-seed-1-6","ModelVersion":"250615"}}},
+				{"Id":"ep-20250601-emb","Name":"emb","Status":"Running","ModelReference":{"FoundationModel":{"Name":"doubao-embedding-large-text","ModelVersion":"250515"}}},
+				{"Id":"ep-20250601-old","Name":"old","Status":"Stopped","ModelReference":{"FoundationModel":{"Name":"doubao-pro-32k","ModelVersion":"241215"}}}]}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	mk := NewModelKit(nil)
+	for typ, want := range map[string][]string{
+		"llm":       {"ep-20250601-chat", "deepseek-v3-250324"},
+		"embedding": {"ep-20250601-emb"},
+	} {
+		resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+			Provider: string(consts.ModelProviderVolcengine),
+			BaseURL:  ts.URL + "/api/v3",
+			APIKey:   "ark-key",
+			Type:     typ,
+
+			VolcengineAccessKey: "AKLT-test",
+			VolcengineSecretKey: "secret",
+		})
+		if err != nil || resp.Error != "" {
+			t.Fatalf("ModelList failed: %v %+v", err, resp)
+		}
+		if len(resp.Models) != len(want) {
+			t.Fatalf("unexpected %s models: %+v", typ, resp.Models)
+		}
+		for i := range want {
+			if resp.Models[i].Model != want[i] {
+				t.Fatalf("unexpected %s models: %+v", typ, resp.Models)
+			}
+		}
+		if typ == "llm" && resp.Models[0].BaseModel != "doubao-seed-1-6-250615" {
+			t.Fatalf("unexpected base model: %+v", resp.Models[0])
+		}
+	}
+}
+
+func TestModelList_VolcengineFallbackToStatic(t *testing.T) {
+	// 模型与接入点列举失败时都退回内置模型列表
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	cases := []struct {
+		name    string
+		handler http.HandlerFunc
+		baseURL string
+		ak      string
+	}{
+		{name: "not found", handler: http.NotFound},
+		{name: "unauthorized", handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusUnauthorized) }},
+		{name: "server error", handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) }},
+		{name: "network error", baseURL: closed.URL + "/api/v3"},
+		{name: "list endpoints failed", ak: "AKLT-test", handler: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}},
+	}
+	mk := NewModelKit(nil)
+	for _, tc := range cases {
+		baseURL := tc.baseURL
+		if tc.handler != nil {
+			ts := httptest.NewServer(tc.handler)
+			defer ts.Close()
+			baseURL = ts.URL + "/api/v3"
+		}
+		resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+			Provider: string(consts.ModelProviderVolcengine),
+			BaseURL:  baseURL,
+			APIKey:   "ark-key",
+			Type:     "rerank",
+
+			VolcengineAccessKey: tc.ak,
+			VolcengineSecretKey: tc.ak,
+		})
+		if err != nil || resp.Error != "" || len(resp.Models) == 0 {
+			t.Errorf("%s: expected static models: %v %+v", tc.name, err, resp)
+		}
+	}
+}
+
+func TestGetReranker_ArkKnowledgeRejectsInferenceEndpoint(t *testing.T) {
+	mk := NewModelKit(nil)
+	_, err := mk.GetReranker(context.Background(), &domain.ModelMetadata{
+		Provider:  consts.ModelProviderVolcengine,
+		ModelName: "m3-v2-rerank",
+		APIKey:    "ark-key",
+
+		VolcengineKnowledgeBaseURL: "https://ark.cn-beijing.volces.com/api/v3",
+		VolcengineKnowledgeAPIKey:  "kb-key",
+	})
+	if err == nil || !strings.Contains(err.Error(), "ark inference endpoint") {
+		t.Fatalf("expected inference endpoint error, got %v", err)
+	}
+	// 未设置知识库 API Key 时不会使用方舟推理的 API Key
+	_, err = mk.GetReranker(context.Background(), &domain.ModelMetadata{
+		Provider:  consts.ModelProviderVolcengine,
+		ModelName: "m3-v2-rerank",
+		APIKey:    "ark-key",
+	})
+	if err == nil || !strings.Contains(err.Error(), "knowledge base api key") {
+		t.Fatalf("expected missing api key error, got %v", err)
+	}
+}
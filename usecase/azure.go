@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/pkg/request"
+)
+
+const (
+	azureDefaultAPIVersion = "2024-10-21"
+	// 数据面列出部署的接口在较新的 api-version 中已移除, 2022-12-01 是仍支持的 GA 版本, 与 APIVersion 无关
+	azureDeploymentsAPIVersion = "2022-12-01"
+)
+
+// listAzureOpenAI 通过数据面 /openai/deployments 列出实际的部署名称与底层模型.
+// 数据面 /openai/models 只返回资源可用的基础模型而不是部署名称, 不能用于调用, 因此不使用;
+// 查询失败时返回错误, 不再退回内置模型列表
+func (m *ModelKit) listAzureOpenAI(req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
+	u, err := url.Parse(strings.TrimSuffix(req.BaseURL, "#"))
+	if err != nil {
+		return &domain.ModelListResp{Error: err.Error()}, nil
+	}
+	client := request.NewClient(u.Scheme, u.Host, httpClient.Timeout, request.WithClient(httpClient))
+	header := request.Header{"api-key": req.APIKey}
+	if req.APIHeader != "" {
+		maps.Copy(header, request.GetHeaderMap(req.APIHeader))
+	}
+
+	resp, err := request.Get[domain.AzureDeploymentsResp](client, azureBasePath(u.Path)+"/openai/deployments",
+		request.WithHeader(header),
+		request.WithQuery(request.Query{"api-version": azureDeploymentsAPIVersion}),
+	)
+	if err != nil {
+		return &domain.ModelListResp{Error: err.Error()}, nil
+	}
+	filtered := FilterModelsByType(resp.ParseModels(), req)
+	return &domain.ModelListResp{Models: filtered}, nil
+}
+
+// azureBasePath 去掉 BaseURL 末尾的 /openai 或 /openai/v1, 保留网关(如 APIM)的路径前缀
+func azureBasePath(p string) string {
+	p = strings.TrimSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/v1")
+	p = strings.TrimSuffix(p, "/openai")
+	return p
+}
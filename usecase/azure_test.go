@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+func TestModelList_AzureDeployments(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// APIM 等网关的路径前缀需要保留, 只请求支持该接口的 api-version
+		if r.URL.Path != "/apim/openai/deployments" || r.Header.Get("api-key") != "azure-key" || r.URL.Query().Get("api-version") != azureDeploymentsAPIVersion {
+			t.Errorf("unexpected request: %s %v", r.URL, r.Header)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[
+			{"id":"prod-chat","model":"gpt-4o","status":"succeeded","object":"deployment"},
+			{"id":"emb-prod","model":"text-embedding-3-large","status":"succeeded","object":"deployment"},
+			{"id":"new-chat","model":"gpt-4.1","status":"creating","object":"deployment"}]}`))
+	}))
+	defer ts.Close()
+
+	mk := NewModelKit(nil)
+	for typ, want := range map[string]string{"llm": "prod-chat", "embedding": "emb-prod"} {
+		resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+			Provider:   string(consts.ModelProviderAzureOpenAI),
+			BaseURL:    ts.URL + "/apim/openai/v1",
+			APIKey:     "azure-key",
+			APIVersion: "2025-04-01-preview",
+			Type:       typ,
+		})
+		if err != nil || resp.Error != "" {
+			t.Fatalf("ModelList failed: %v %+v", err, resp)
+		}
+		if len(resp.Models) != 1 || resp.Models[0].Model != want {
+			t.Fatalf("unexpected %s models: %+v", typ, resp.Models)
+		}
+	}
+}
+
+func TestModelList_AzureNotFound(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	mk := NewModelKit(nil)
+	resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+		Provider: string(consts.ModelProviderAzureOpenAI),
+		BaseURL:  ts.URL,
+		APIKey:   "azure-key",
+		Type:     "llm",
+	})
+	if err != nil || resp.Error != "status code: 404" || len(resp.Models) != 0 || requests != 1 {
+		t.Fatalf("expected a single failed request: %v %+v requests=%d", err, resp, requests)
+	}
+}
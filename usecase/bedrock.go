@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+
+	bedrockEmb "github.com/chaitin/ModelKit/v2/components/embedder/bedrock"
+	"github.com/chaitin/ModelKit/v2/components/model/bedrock"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/pkg/extrabody"
+	"github.com/chaitin/ModelKit/v2/pkg/request"
+	"github.com/chaitin/ModelKit/v2/pkg/sigv4"
+	"github.com/chaitin/ModelKit/v2/utils"
+)
+
+const (
+	bedrockDefaultRegion = "us-east-1"
+	// bedrock 与 bedrock-runtime 使用同一个签名服务名
+	bedrockSigningService = "bedrock"
+)
+
+type bedrockAuth struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+	APIKey          string
+	BaseURL         string
+}
+
+func bedrockAuthFromMetadata(md *domain.ModelMetadata) bedrockAuth {
+	return bedrockAuth{
+		AccessKeyID:     md.AWSAccessKeyID,
+		SecretAccessKey: md.AWSSecretAccessKey,
+		SessionToken:    md.AWSSessionToken,
+		Region:          md.AWSRegion,
+		APIKey:          md.APIKey,
+		BaseURL:         md.BaseURL,
+	}
+}
+
+// region 优先使用显式配置, 其次从 bedrock(-runtime).<region>.amazonaws.com 中解析
+func (a bedrockAuth) region() string {
+	if a.Region != "" {
+		return a.Region
+	}
+	if u, err := url.Parse(a.BaseURL); err == nil {
+		parts := strings.Split(u.Hostname(), ".")
+		if len(parts) >= 4 && strings.HasPrefix(parts[0], "bedrock") {
+			return parts[1]
+		}
+	}
+	return bedrockDefaultRegion
+}
+
+func (a bedrockAuth) runtimeURL() string {
+	if a.BaseURL != "" {
+		return a.BaseURL
+	}
+	return fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", a.region())
+}
+
+// controlURL 模型列表使用 bedrock 控制面地址, 自定义地址原样使用
+func (a bedrockAuth) controlURL() string {
+	if a.BaseURL == "" {
+		return fmt.Sprintf("https://bedrock.%s.amazonaws.com", a.region())
+	}
+	u, err := url.Parse(strings.TrimSuffix(a.BaseURL, "#"))
+	if err != nil {
+		return a.BaseURL
+	}
+	if host, ok := strings.CutPrefix(u.Host, "bedrock-runtime."); ok {
+		u.Host = "bedrock." + host
+	}
+	return u.String()
+}
+
+// httpClient 使用 AccessKey 时进行 SigV4 签名, 否则将 APIKey 作为 Bedrock API Key
+func (a bedrockAuth) httpClient(base *http.Client) (*http.Client, error) {
+	if a.AccessKeyID != "" || a.SecretAccessKey != "" {
+		signer, err := sigv4.NewSigner(sigv4.Credentials{
+			AccessKeyID:     a.AccessKeyID,
+			SecretAccessKey: a.SecretAccessKey,
+			SessionToken:    a.SessionToken,
+		}, a.region(), bedrockSigningService)
+		if err != nil {
+			return nil, err
+		}
+		client := &http.Client{}
+		var rt http.RoundTripper
+		if base != nil {
+			client.Timeout = base.Timeout
+			rt = base.Transport
+		}
+		client.Transport = sigv4.NewTransport(signer, rt)
+		return client, nil
+	}
+	if a.APIKey != "" {
+		hc := utils.GetHttpClientWithAPIHeaderMap("Authorization=Bearer " + a.APIKey)
+		if base != nil {
+			hc.Timeout = base.Timeout
+		}
+		return hc, nil
+	}
+	return nil, errors.New("missing aws credentials: set access key id/secret access key or api key")
+}
+
+func newBedrockChatModel(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
+	auth := bedrockAuthFromMetadata(md)
+	hc, err := auth.httpClient(nil)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &bedrock.ChatModelConfig{
+		Model:       md.ModelName,
+		BaseURL:     auth.runtimeURL(),
+		HTTPClient:  extrabody.WrapClient(hc, md.ExtraBody),
+		MaxTokens:   md.MaxTokens,
+		Temperature: md.Temperature,
+		TopP:        md.TopP,
+		// Converse 没有原生的结构化输出, 通过强制调用输出工具实现
+		ResponseSchema: responseSchema(md),
+	}
+	if len(md.Stop) > 0 {
+		cfg.StopSequences = md.Stop
+	}
+	return bedrock.NewChatModel(ctx, cfg)
+}
+
+func newBedrockEmbedder(ctx context.Context, md *domain.ModelMetadata) (embedding.Embedder, error) {
+	auth := bedrockAuthFromMetadata(md)
+	hc, err := auth.httpClient(nil)
+	if err != nil {
+		return nil, err
+	}
+	return bedrockEmb.NewEmbedder(ctx, &bedrockEmb.EmbeddingConfig{
+		Model:      md.ModelName,
+		BaseURL:    auth.runtimeURL(),
+		HTTPClient: hc,
+		Dimension:  md.EmbedderParam.Dimension,
+		TextType:   md.EmbedderParam.TextType,
+	})
+}
+
+func (m *ModelKit) listBedrock(req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
+	auth := bedrockAuth{
+		AccessKeyID:     req.AWSAccessKeyID,
+		SecretAccessKey: req.AWSSecretAccessKey,
+		SessionToken:    req.AWSSessionToken,
+		Region:          req.AWSRegion,
+		APIKey:          req.APIKey,
+		BaseURL:         req.BaseURL,
+	}
+	hc, err := auth.httpClient(httpClient)
+	if err != nil {
+		return &domain.ModelListResp{Error: err.Error()}, nil
+	}
+	u, err := url.Parse(auth.controlURL())
+	if err != nil {
+		return &domain.ModelListResp{Error: err.Error()}, nil
+	}
+	client := request.NewClient(u.Scheme, u.Host, hc.Timeout, request.WithClient(hc))
+	basePath := strings.TrimSuffix(u.Path, "/")
+
+	resp, err := request.Get[domain.BedrockListModelResp](client, basePath+"/foundation-models")
+	if err != nil {
+		return &domain.ModelListResp{Error: err.Error()}, nil
+	}
+	models := resp.ParseModels()
+
+	// 跨区域推理配置文件(如 us.anthropic.xxx)无法从基础模型列表获取, 失败时忽略
+	profiles, err := request.Get[domain.BedrockListInferenceProfileResp](client, basePath+"/inference-profiles", request.WithQuery(request.Query{"maxResults": "1000"}))
+	if err == nil {
+		models = append(models, profiles.ParseModels()...)
+	} else if m.logger != nil {
+		m.logger.Info("list bedrock inference profiles failed", "error", err)
+	}
+
+	filtered := FilterModelsByType(models, req)
+	return &domain.ModelListResp{Models: filtered}, nil
+}
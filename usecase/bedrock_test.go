@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+	"github.com/chaitin/ModelKit/v2/pkg/sigv4"
+)
+
+const (
+	testAWSAccessKeyID     = "AKIDEXAMPLE"
+	testAWSSecretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testAWSRegion          = "us-west-2"
+)
+
+// testAWSCredentials 与 usecase 按 AWSAccessKeyID 等字段构造的签名凭证一致
+var testAWSCredentials = sigv4.Credentials{AccessKeyID: testAWSAccessKeyID, SecretAccessKey: testAWSSecretAccessKey}
+
+// 请求与流式解析的细节见 components/model/bedrock 与 components/embedder/bedrock, 这里校验凭证与签名的接入
+func TestGetChatModel_Bedrock(t *testing.T) {
+	modelID := "anthropic.claude-3-haiku-20240307-v1:0"
+	ts := standin.New(t, standin.Routes{
+		"/model/" + modelID + "/converse": standin.JSON(`{
+			"output": {"message": {"role": "assistant", "content": [{"text": "Hello there!"}]}},
+			"stopReason": "end_turn",
+			"usage": {"inputTokens": 10, "outputTokens": 5, "totalTokens": 15}
+		}`),
+		"/model/amazon.titan-embed-text-v2:0/invoke": standin.JSON(`{"embedding":[0.1,0.2,0.3],"inputTextTokenCount":4}`),
+	}, standin.SigV4(testAWSCredentials, testAWSRegion, "bedrock"))
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	md := &domain.ModelMetadata{
+		Provider:           consts.ModelProviderAWSBedrock,
+		ModelName:          modelID,
+		BaseURL:            ts.URL,
+		AWSAccessKeyID:     testAWSAccessKeyID,
+		AWSSecretAccessKey: testAWSSecretAccessKey,
+		AWSRegion:          testAWSRegion,
+	}
+	chatModel, err := mk.GetChatModel(ctx, md)
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	resp, err := chatModel.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if resp.Content != "Hello there!" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	md.ModelName = "amazon.titan-embed-text-v2:0"
+	embedder, err := mk.GetEmbedder(ctx, md)
+	if err != nil {
+		t.Fatalf("GetEmbedder failed: %v", err)
+	}
+	embResp, err := mk.UseEmbedder(ctx, embedder, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("UseEmbedder failed: %v", err)
+	}
+	if len(embResp.Embeddings) != 2 || embResp.Usage.TotalTokens != 8 {
+		t.Fatalf("unexpected embeddings: %+v", embResp)
+	}
+}
+
+func TestModelList_Bedrock(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"/foundation-models": standin.JSON(`{"modelSummaries":[
+			{"modelId":"anthropic.claude-3-haiku-20240307-v1:0","inferenceTypesSupported":["ON_DEMAND"]},
+			{"modelId":"amazon.titan-embed-text-v2:0","inferenceTypesSupported":["ON_DEMAND"]},
+			{"modelId":"anthropic.claude-3-7-sonnet-20250219-v1:0","inferenceTypesSupported":["INFERENCE_PROFILE"]}
+		]}`),
+		"/inference-profiles": standin.JSON(`{"inferenceProfileSummaries":[{"inferenceProfileId":"us.anthropic.claude-3-7-sonnet-20250219-v1:0","status":"ACTIVE"}]}`),
+	}, standin.SigV4(testAWSCredentials, testAWSRegion, "bedrock"))
+
+	mk := NewModelKit(nil)
+	resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+		Provider:           string(consts.ModelProviderAWSBedrock),
+		BaseURL:            ts.URL,
+		Type:               "chat",
+		AWSAccessKeyID:     testAWSAccessKeyID,
+		AWSSecretAccessKey: testAWSSecretAccessKey,
+		AWSRegion:          testAWSRegion,
+	})
+	if err != nil {
+		t.Fatalf("ModelList failed: %v", err)
+	}
+	if resp.Error != "" {
+		t.Fatalf("ModelList returned error: %s", resp.Error)
+	}
+	got := make([]string, 0, len(resp.Models))
+	for _, m := range resp.Models {
+		got = append(got, m.Model)
+	}
+	want := "anthropic.claude-3-haiku-20240307-v1:0,us.anthropic.claude-3-7-sonnet-20250219-v1:0"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected models: %v", got)
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+func TestGetReranker_CohereProtocol(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/rerank" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		var body struct {
+			Documents       []string `json:"documents"`
+			TopN            int      `json:"top_n"`
+			MaxTokensPerDoc int      `json:"max_tokens_per_doc"`
+			ReturnDocuments *bool    `json:"return_documents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		// v2 不支持 return_documents, 文档由本地回填
+		if len(body.Documents) != 2 || body.Documents[1] != "\"title\": \"火鸡\"\n\"weight\": 3\n" || body.TopN != 1 || body.MaxTokensPerDoc != 512 || body.ReturnDocuments != nil {
+			t.Errorf("unexpected request: %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"r1","results":[{"index":1,"relevance_score":0.93}],"meta":{"billed_units":{"search_units":1},"tokens":{"input_tokens":20,"output_tokens":0}}}`))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	// 通用提供商显式选择 Cohere 协议
+	transport := &countingTransport{}
+	rk, err := mk.GetReranker(ctx, &domain.ModelMetadata{
+		Provider:       consts.ModelProviderOther,
+		ModelName:      "rerank-v3.5",
+		BaseURL:        ts.URL,
+		APIKey:         "co-test",
+		RerankProtocol: consts.RerankProtocolCohere,
+		HTTPClient:     &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("GetReranker failed: %v", err)
+	}
+	n := 1
+	maxTokens := 512
+	resp, err := rk.Rerank(ctx, domain.RerankRequest{
+		Query: "动物",
+		StructuredDocuments: []map[string]any{
+			{"title": "火"},
+			{"title": "火鸡", "weight": 3},
+		},
+		ReturnDocuments: true,
+		N:               &n,
+		MaxTokensPerDoc: &maxTokens,
+	})
+	if err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Index != 1 || resp.Results[0].Document == "" || resp.Usage == nil || resp.Usage.InputTokens != 20 {
+		t.Fatalf("unexpected rerank result: %+v", resp)
+	}
+	if got := transport.n.Load(); got != 1 {
+		t.Fatalf("expected the request through the custom client, got %d", got)
+	}
+}
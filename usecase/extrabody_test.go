@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+	"github.com/chaitin/ModelKit/v2/pkg/extrabody"
+)
+
+func TestGetChatModel_ExtraBody(t *testing.T) {
+	var got map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer ts.Close()
+
+	enable := true
+	ctx := context.Background()
+	cm, err := NewModelKit(nil).GetChatModel(ctx, &domain.ModelMetadata{
+		Provider:       consts.ModelProviderBaiLian,
+		ModelName:      "qwen-plus",
+		BaseURL:        ts.URL + "/v1",
+		APIKey:         "sk-test",
+		EnableThinking: &enable,
+		ExtraBody: map[string]any{
+			"enable_search":   true,
+			"enable_thinking": false,
+			"search_options":  map[string]any{"forced_search": true, "search_strategy": "turbo"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+
+	if _, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("hi")}); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if got["model"] != "qwen-plus" || got["enable_search"] != true || got["enable_thinking"] != false {
+		t.Fatalf("unexpected request body: %v", got)
+	}
+
+	reqCtx := extrabody.WithContext(ctx, map[string]any{
+		"search_options": map[string]any{"search_strategy": "max"},
+		"enable_search":  nil,
+	})
+	if _, err := cm.Generate(reqCtx, []*schema.Message{schema.UserMessage("hi")}); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	opts, _ := got["search_options"].(map[string]any)
+	if _, ok := got["enable_search"]; ok || opts["forced_search"] != true || opts["search_strategy"] != "max" {
+		t.Fatalf("unexpected request body with overrides: %v", got)
+	}
+}
+
+func TestGetChatModel_OllamaExtraBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			KeepAlive string         `json:"keep_alive"`
+			Options   map[string]any `json:"options"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/chat" || body.KeepAlive != "10m" || body.Options["num_ctx"] != float64(8192) || body.Options["temperature"] != 0.3 {
+			t.Errorf("unexpected request: %s %+v", r.URL.Path, body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"qwen3","message":{"role":"assistant","content":"ok"},"done":true}`))
+	}))
+	defer ts.Close()
+
+	temperature := float32(0.3)
+	cm, err := NewModelKit(nil).GetChatModel(context.Background(), &domain.ModelMetadata{
+		Provider:    consts.ModelProviderOllama,
+		ModelName:   "qwen3",
+		BaseURL:     ts.URL,
+		Temperature: &temperature,
+		ExtraBody: map[string]any{
+			"keep_alive": "10m",
+			"options":    map[string]any{"num_ctx": 8192},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	if _, err := cm.Generate(context.Background(), []*schema.Message{schema.UserMessage("hi")}); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+}
+
+func TestGetChatModel_BedrockExtraBodySigned(t *testing.T) {
+	modelID := "anthropic.claude-3-haiku-20240307-v1:0"
+	ts := standin.New(t, standin.Routes{"/model/" + modelID + "/converse": func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		fields, _ := body["additionalModelRequestFields"].(map[string]any)
+		if fields["top_k"] != float64(20) {
+			t.Errorf("unexpected request body: %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"output":{"message":{"role":"assistant","content":[{"text":"ok"}]}},"stopReason":"end_turn","usage":{"inputTokens":1,"outputTokens":1,"totalTokens":2}}`))
+	}}, standin.SigV4(testAWSCredentials, testAWSRegion, "bedrock"))
+
+	cm, err := NewModelKit(nil).GetChatModel(context.Background(), &domain.ModelMetadata{
+		Provider:           consts.ModelProviderAWSBedrock,
+		ModelName:          modelID,
+		BaseURL:            ts.URL,
+		AWSAccessKeyID:     testAWSAccessKeyID,
+		AWSSecretAccessKey: testAWSSecretAccessKey,
+		AWSRegion:          testAWSRegion,
+		ExtraBody:          map[string]any{"additionalModelRequestFields": map[string]any{"top_k": 20}},
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	msg, err := cm.Generate(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+	if err != nil || msg.Content != "ok" {
+		t.Fatalf("Generate failed: %v %+v", err, msg)
+	}
+}
+
+func TestCheckModel_InvalidExtraBody(t *testing.T) {
+	mk := NewModelKit(nil)
+	for _, extra := range []map[string]any{
+		{"model": "other"},
+		{"": true},
+		{"bad": func() {}},
+	} {
+		resp, err := mk.CheckModel(context.Background(), &domain.CheckModelReq{
+			Provider:  string(consts.ModelProviderOpenAI),
+			Model:     "gpt-4o-mini",
+			BaseURL:   "http://127.0.0.1:1/v1",
+			APIKey:    "sk-test",
+			Type:      string(consts.ModelTypeChat),
+			ExtraBody: extra,
+		})
+		if err != nil || !strings.HasPrefix(resp.Error, "invalid extra_body") {
+			t.Fatalf("expected extra_body %v to be rejected: %v %+v", extra, err, resp)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+	"github.com/chaitin/ModelKit/v2/pkg/extract"
+)
+
+type testInvoice struct {
+	Number   string  `json:"number" jsonschema:"description=发票号码"`
+	Currency string  `json:"currency" jsonschema:"enum=CNY,enum=USD"`
+	Amount   float64 `json:"amount" jsonschema:"minimum=0"`
+	Items    []struct {
+		Name     string `json:"name"`
+		Quantity int    `json:"quantity"`
+	} `json:"items"`
+	Note string `json:"note,omitempty"`
+}
+
+// 校验与修复重试的细节见 pkg/extract, 这里校验 ExtractWithMetadata 设置的 ResponseFormat 经 OpenAI 兼容接口发送
+func TestExtract_ResponseFormat(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"POST /chat/completions": func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				ResponseFormat struct {
+					Type       string `json:"type"`
+					JSONSchema struct {
+						Name string `json:"name"`
+					} `json:"json_schema"`
+				} `json:"response_format"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.ResponseFormat.Type != "json_schema" || body.ResponseFormat.JSONSchema.Name != "testInvoice" {
+				t.Errorf("unexpected response_format: %+v", body.ResponseFormat)
+			}
+			standin.WriteJSON(w, map[string]any{
+				"id": "1", "object": "chat.completion",
+				"choices": []map[string]any{{"index": 0, "finish_reason": "stop", "message": map[string]any{
+					"role": "assistant", "content": `{"number":"INV-1","currency":"CNY","amount":12.5,"items":[{"name":"pen","quantity":5}]}`,
+				}}},
+			})
+		},
+	})
+
+	ctx := context.Background()
+	invoice, err := extract.ExtractWithMetadata[testInvoice](ctx, NewModelKit(nil).GetChatModel, &domain.ModelMetadata{
+		Provider:  consts.ModelProviderOpenAI,
+		ModelName: "gpt-4o-mini",
+		BaseURL:   ts.URL,
+		APIKey:    "sk-test",
+	}, []*schema.Message{schema.UserMessage("发票 INV-1, 5 支笔共 12.5 元")})
+	if err != nil {
+		t.Fatalf("ExtractWithMetadata failed: %v", err)
+	}
+	if invoice.Number != "INV-1" || invoice.Amount != 12.5 || len(invoice.Items) != 1 || invoice.Items[0].Quantity != 5 {
+		t.Fatalf("unexpected invoice: %+v", invoice)
+	}
+}
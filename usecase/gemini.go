@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino-ext/libs/acl/openai"
+	"github.com/eino-contrib/jsonschema"
+	"google.golang.org/genai"
+
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+var geminiHarmBlockThresholds = []genai.HarmBlockThreshold{
+	genai.HarmBlockThresholdBlockLowAndAbove,
+	genai.HarmBlockThresholdBlockMediumAndAbove,
+	genai.HarmBlockThresholdBlockOnlyHigh,
+	genai.HarmBlockThresholdBlockNone,
+	genai.HarmBlockThresholdOff,
+}
+
+// geminiParam 将通用的思考参数合并到 GeminiParam, GeminiParam 中已设置的值优先
+func geminiParam(md *domain.ModelMetadata) domain.GeminiParam {
+	p := md.GeminiParam
+	if p.ThinkingBudget != nil || p.ThinkingLevel != "" {
+		return p
+	}
+	switch {
+	case md.EnableThinking != nil && !*md.EnableThinking:
+		p.ThinkingBudget = new(int32)
+	case md.ThinkingBudget != nil:
+		budget := int32(*md.ThinkingBudget)
+		p.ThinkingBudget = &budget
+	case md.ReasoningEffort != "" && strings.HasPrefix(md.ModelName, "gemini-3"):
+		// Gemini 3 仅支持 low、high 两档
+		p.ThinkingLevel = "high"
+		if md.ReasoningEffort == "minimal" || md.ReasoningEffort == "low" {
+			p.ThinkingLevel = "low"
+		}
+	}
+	return p
+}
+
+// geminiThinkingConfig 默认返回思考摘要, 预算为空时由模型决定
+func geminiThinkingConfig(p domain.GeminiParam) (*genai.ThinkingConfig, error) {
+	if p.ThinkingBudget != nil && p.ThinkingLevel != "" {
+		return nil, errors.New("thinking_budget and thinking_level can not be set at the same time")
+	}
+	includeThoughts := true
+	if p.IncludeThoughts != nil {
+		includeThoughts = *p.IncludeThoughts
+	}
+	// 关闭思考时不能要求返回思考内容
+	if p.ThinkingBudget != nil && *p.ThinkingBudget == 0 {
+		includeThoughts = false
+	}
+	return &genai.ThinkingConfig{
+		IncludeThoughts: includeThoughts,
+		ThinkingBudget:  p.ThinkingBudget,
+	}, nil
+}
+
+// geminiSafetySettings 按类别排序, 保证请求稳定
+func geminiSafetySettings(p domain.GeminiParam) ([]*genai.SafetySetting, error) {
+	if len(p.SafetySettings) == 0 {
+		return nil, nil
+	}
+	settings := make([]*genai.SafetySetting, 0, len(p.SafetySettings))
+	for c, t := range p.SafetySettings {
+		category := strings.ToUpper(c)
+		if !strings.HasPrefix(category, "HARM_CATEGORY_") {
+			category = "HARM_CATEGORY_" + category
+		}
+		threshold := genai.HarmBlockThreshold(strings.ToUpper(t))
+		if !slices.Contains(geminiHarmBlockThresholds, threshold) {
+			return nil, fmt.Errorf("invalid safety threshold %q for %s", t, category)
+		}
+		settings = append(settings, &genai.SafetySetting{
+			Category:  genai.HarmCategory(category),
+			Threshold: threshold,
+		})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Category < settings[j].Category })
+	return settings, nil
+}
+
+// geminiResponseJSONSchema GeminiParam.ResponseSchema 优先, 其次使用 OpenAI 风格的 json_schema
+func geminiResponseJSONSchema(md *domain.ModelMetadata) (*jsonschema.Schema, error) {
+	if md.GeminiParam.ResponseSchema != nil {
+		raw, err := json.Marshal(md.GeminiParam.ResponseSchema)
+		if err != nil {
+			return nil, err
+		}
+		var s jsonschema.Schema
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("invalid response schema: %w", err)
+		}
+		return &s, nil
+	}
+	if rf := md.ResponseFormat; rf != nil && rf.Type == openai.ChatCompletionResponseFormatTypeJSONSchema && rf.JSONSchema != nil {
+		return rf.JSONSchema.JSONSchema, nil
+	}
+	return nil, nil
+}
+
+// geminiExtraBody eino gemini 未暴露的生成参数, 合并到请求的 generationConfig 中
+func geminiExtraBody(md *domain.ModelMetadata) map[string]any {
+	gc := map[string]any{}
+	if len(md.Stop) > 0 {
+		gc["stopSequences"] = md.Stop
+	}
+	if md.Seed != nil {
+		gc["seed"] = *md.Seed
+	}
+	if md.PresencePenalty != nil {
+		gc["presencePenalty"] = *md.PresencePenalty
+	}
+	if md.FrequencyPenalty != nil {
+		gc["frequencyPenalty"] = *md.FrequencyPenalty
+	}
+	if rf := md.ResponseFormat; rf != nil && rf.Type == openai.ChatCompletionResponseFormatTypeJSONObject {
+		gc["responseMimeType"] = "application/json"
+	}
+	if md.GeminiParam.ResponseMIMEType != "" {
+		gc["responseMimeType"] = md.GeminiParam.ResponseMIMEType
+	}
+	if level := geminiParam(md).ThinkingLevel; level != "" {
+		gc["thinkingConfig"] = map[string]any{"thinkingLevel": strings.ToLower(level)}
+	}
+	if len(gc) == 0 {
+		return nil
+	}
+	return map[string]any{"generationConfig": gc}
+}
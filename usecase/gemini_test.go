@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+// geminiRelayRoutes 模拟挂在 /relay 路径下的 Gemini 中转服务, 模型列表分两页返回
+var geminiRelayRoutes = standin.Routes{
+	"GET /relay/v1beta/models": func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageToken") == "" {
+			standin.JSON(`{"models":[
+				{"name":"models/gemini-2.5-flash","supportedGenerationMethods":["generateContent","countTokens"]},
+				{"name":"models/imagen-3.0-generate-002","supportedGenerationMethods":["predict"]}],"nextPageToken":"p2"}`)(w, r)
+			return
+		}
+		standin.JSON(`{"models":[{"name":"models/gemini-embedding-001","supportedGenerationMethods":["embedContent"]}]}`)(w, r)
+	},
+	"POST /relay/v1beta/models/gemini-2.5-flash:generateContent": standin.JSON(`{"candidates":[{"content":{"role":"model","parts":[{"text":"pong"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":3,"candidatesTokenCount":1,"totalTokenCount":4}}`),
+}
+
+func TestModelList_GeminiRelay(t *testing.T) {
+	ts := standin.New(t, geminiRelayRoutes, standin.Header("X-Relay-Token", "relay"), standin.Header("x-goog-api-key", "gm-key"))
+
+	mk := NewModelKit(nil)
+	for typ, want := range map[string]string{"llm": "gemini-2.5-flash", "embedding": "gemini-embedding-001"} {
+		resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+			Provider:  string(consts.ModelProviderGemini),
+			BaseURL:   ts.URL + "/relay/v1beta",
+			APIKey:    "gm-key",
+			APIHeader: "X-Relay-Token=relay",
+			Type:      typ,
+		})
+		if err != nil || resp.Error != "" {
+			t.Fatalf("ModelList failed: %v %+v", err, resp)
+		}
+		if len(resp.Models) != 1 || resp.Models[0].Model != want {
+			t.Fatalf("unexpected %s models: %+v", typ, resp.Models)
+		}
+	}
+}
+
+func TestModelList_GeminiIteratorError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"code":403,"message":"API key not valid","status":"PERMISSION_DENIED"}}`))
+	}))
+	defer ts.Close()
+
+	mk := NewModelKit(nil)
+	resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+		Provider: string(consts.ModelProviderGemini),
+		BaseURL:  ts.URL,
+		APIKey:   "bad",
+		Type:     "llm",
+	})
+	if err != nil {
+		t.Fatalf("ModelList failed: %v", err)
+	}
+	if !strings.Contains(resp.Error, "API key not valid") {
+		t.Fatalf("expected iterator error, got: %+v", resp)
+	}
+}
+
+func TestGetChatModel_GeminiRelay(t *testing.T) {
+	ts := standin.New(t, geminiRelayRoutes, standin.Header("X-Relay-Token", "relay"), standin.Header("x-goog-api-key", "gm-key"))
+
+	mk := NewModelKit(nil)
+	resp, err := mk.CheckModel(context.Background(), &domain.CheckModelReq{
+		Provider:  string(consts.ModelProviderGemini),
+		Model:     "gemini-2.5-flash",
+		BaseURL:   ts.URL + "/relay/v1beta",
+		APIKey:    "gm-key",
+		APIHeader: "X-Relay-Token=relay",
+		Type:      string(consts.ModelTypeChat),
+	})
+	if err != nil {
+		t.Fatalf("CheckModel failed: %v", err)
+	}
+	if resp.Error != "" || resp.Content != "pong" {
+		t.Fatalf("unexpected check result: %+v", resp)
+	}
+}
+
+func TestGetChatModel_GeminiParams(t *testing.T) {
+	var body struct {
+		GenerationConfig map[string]any   `json:"generationConfig"`
+		SafetySettings   []map[string]any `json:"safetySettings"`
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"{\"answer\":\"pong\"}"}]},"finishReason":"STOP"}]}`))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	seed := 42
+	cm, err := mk.GetChatModel(ctx, &domain.ModelMetadata{
+		Provider:  consts.ModelProviderGemini,
+		ModelName: "gemini-3-pro-preview",
+		BaseURL:   ts.URL,
+		APIKey:    "gm-key",
+		Stop:      []string{"END"},
+		Seed:      &seed,
+		GeminiParam: domain.GeminiParam{
+			ThinkingLevel:  "LOW",
+			SafetySettings: map[string]string{"harassment": "block_none", "HARM_CATEGORY_HATE_SPEECH": "BLOCK_ONLY_HIGH"},
+			ResponseSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"answer": map[string]any{"type": "string"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	if _, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("ping")}); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	gc := body.GenerationConfig
+	if stop, _ := gc["stopSequences"].([]any); len(stop) != 1 || stop[0] != "END" || gc["seed"] != float64(42) {
+		t.Fatalf("unexpected generationConfig: %v", gc)
+	}
+	if gc["responseMimeType"] != "application/json" || gc["responseJsonSchema"] == nil {
+		t.Fatalf("unexpected response format: %v", gc)
+	}
+	thinking, _ := gc["thinkingConfig"].(map[string]any)
+	if thinking["thinkingLevel"] != "low" || thinking["includeThoughts"] != true {
+		t.Fatalf("unexpected thinkingConfig: %v", thinking)
+	}
+	if len(body.SafetySettings) != 2 || body.SafetySettings[0]["category"] != "HARM_CATEGORY_HARASSMENT" || body.SafetySettings[0]["threshold"] != "BLOCK_NONE" {
+		t.Fatalf("unexpected safetySettings: %v", body.SafetySettings)
+	}
+}
+
+func TestGetChatModel_GeminiInvalidParams(t *testing.T) {
+	budget := int32(1024)
+	mk := NewModelKit(nil)
+	for name, p := range map[string]domain.GeminiParam{
+		"budget and level": {ThinkingBudget: &budget, ThinkingLevel: "high"},
+		"bad threshold":    {SafetySettings: map[string]string{"harassment": "block_some"}},
+	} {
+		if _, err := mk.GetChatModel(context.Background(), &domain.ModelMetadata{
+			Provider:    consts.ModelProviderGemini,
+			ModelName:   "gemini-2.5-flash",
+			APIKey:      "gm-key",
+			GeminiParam: p,
+		}); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestGeminiClientConfig_BaseURL(t *testing.T) {
+	for _, tc := range []struct {
+		baseURL, wantBase, wantVersion string
+	}{
+		{"https://generativelanguage.googleapis.com", "https://generativelanguage.googleapis.com/", ""},
+		{"https://generativelanguage.googleapis.com/v1beta", "https://generativelanguage.googleapis.com/", "v1beta"},
+		{"https://generativelanguage.googleapis.com/v1beta/openai/", "https://generativelanguage.googleapis.com/", "v1beta"},
+		{"https://relay.example.com/gemini/v1/openai", "https://relay.example.com/gemini/", "v1"},
+		{"https://relay.example.com/openai-proxy/v1alpha#", "https://relay.example.com/openai-proxy/", "v1alpha"},
+	} {
+		cfg := geminiClientConfig("gm-key", tc.baseURL, "", nil)
+		if cfg.HTTPOptions.BaseURL != tc.wantBase || cfg.HTTPOptions.APIVersion != tc.wantVersion {
+			t.Errorf("%s: got base=%q version=%q", tc.baseURL, cfg.HTTPOptions.BaseURL, cfg.HTTPOptions.APIVersion)
+		}
+	}
+}
+
+func TestCheckModel_GeminiImageUsesChatClient(t *testing.T) {
+	var imageRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if r.Header.Get("X-Relay-Token") != "relay" || body["cachedContent"] != "cachedContents/abc" {
+			t.Errorf("request missed chat client settings: %v %v", r.Header, body)
+		}
+		if strings.Contains(fmt.Sprint(body["contents"]), "inlineData") {
+			imageRequests++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"Dog"}]},"finishReason":"STOP"}]}`))
+	}))
+	defer ts.Close()
+
+	mk := NewModelKit(nil)
+	resp, err := mk.CheckModel(context.Background(), &domain.CheckModelReq{
+		Provider:  string(consts.ModelProviderGemini),
+		Model:     "gemini-2.5-flash",
+		BaseURL:   ts.URL + "/v1beta/openai/",
+		APIKey:    "gm-key",
+		APIHeader: "X-Relay-Token=relay",
+		Type:      string(consts.ModelTypeChat),
+		ExtraBody: map[string]any{"cachedContent": "cachedContents/abc"},
+		Param:     &domain.ModelParam{SupportImages: true},
+	})
+	if err != nil || resp.Error != "" {
+		t.Fatalf("CheckModel failed: %v %+v", err, resp)
+	}
+	if imageRequests != 1 {
+		t.Fatalf("expected one image request, got %d", imageRequests)
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+func TestModelList_GithubCapabilities(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/models" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id":"openai/gpt-4.1","capabilities":["streaming","tool-calling"],"limits":{"max_input_tokens":1048576,"max_output_tokens":32768},"supported_input_modalities":["text","image"],"supported_output_modalities":["text"]},
+			{"id":"microsoft/phi-4","capabilities":["streaming"],"limits":{"max_input_tokens":16384,"max_output_tokens":16384},"supported_input_modalities":["text"],"supported_output_modalities":["text"]},
+			{"id":"openai/text-embedding-3-small","capabilities":[],"limits":{"max_input_tokens":8191},"supported_input_modalities":["text"],"supported_output_modalities":["embeddings"]}]`))
+	}))
+	defer ts.Close()
+
+	mk := NewModelKit(nil)
+	list := func(typ string) []domain.ModelListItem {
+		resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+			Provider: string(consts.ModelProviderGithub),
+			BaseURL:  ts.URL + "/catalog",
+			APIKey:   "ghp_test",
+			Type:     typ,
+		})
+		if err != nil || resp.Error != "" {
+			t.Fatalf("ModelList failed: %v %+v", err, resp)
+		}
+		return resp.Models
+	}
+
+	for typ, want := range map[string][]string{
+		"llm":           {"openai/gpt-4.1", "microsoft/phi-4"},
+		"analysis-vl":   {"openai/gpt-4.1"},
+		"function_call": {"openai/gpt-4.1"},
+		"embedding":     {"openai/text-embedding-3-small"},
+	} {
+		got := list(typ)
+		if len(got) != len(want) {
+			t.Fatalf("unexpected %s models: %+v", typ, got)
+		}
+		for i := range want {
+			if got[i].Model != want[i] {
+				t.Fatalf("unexpected %s models: %+v", typ, got)
+			}
+		}
+	}
+
+	vl := list("analysis-vl")[0]
+	if vl.ContextLength != 1048576 || vl.MaxOutputTokens != 32768 || len(vl.InputModalities) != 2 {
+		t.Fatalf("unexpected metadata: %+v", vl)
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	openaiEmb "github.com/cloudwego/eino-ext/components/embedding/openai"
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+
+	baaiReranker "github.com/chaitin/ModelKit/v2/components/reranker/baai"
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+// GPUStack 各类接口相对服务根地址的路径
+const (
+	gpustackManagementPath = "/v1"
+	gpustackOpenAIPath     = "/v1-openai"
+	gpustackRerankPath     = "/v1/rerank"
+)
+
+// gpustackURL 将用户填写的 /v1、/v1-openai 等地址转换为服务根地址下的指定接口, 以 # 结尾时原样使用
+func gpustackURL(baseURL, apiPath string) string {
+	if strings.HasSuffix(baseURL, "#") {
+		return baseURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL
+	}
+	// 从第一个 v1 或 v1-openai 路径段截断, 保留反向代理的路径前缀
+	segments := strings.Split(u.Path, "/")
+	for i, seg := range segments {
+		if seg == "v1" || seg == "v1-openai" {
+			segments = segments[:i]
+			break
+		}
+	}
+	u.Path = strings.TrimSuffix(strings.Join(segments, "/"), "/") + apiPath
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+func (m *ModelKit) listGPUStack(req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
+	provider := consts.ParseModelProvider(req.Provider)
+	// 类别与推理后端只在管理接口 /v1/models 中返回
+	listReq := *req
+	listReq.BaseURL = gpustackURL(req.BaseURL, gpustackManagementPath)
+	models, err := reqModelListApi(&listReq, httpClient, &domain.GPUStackListModelResp{})
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Error("GPUStack list model failed", "error", err, "models: ", models)
+		}
+		msg := generateBaseURLFixSuggestion(err.Error(), req.BaseURL, provider)
+		if msg == "" {
+			return &domain.ModelListResp{Error: err.Error()}, nil
+		}
+		return &domain.ModelListResp{Error: msg}, nil
+	}
+	filtered := FilterModelsByType(models, req)
+	return &domain.ModelListResp{Models: filtered}, nil
+}
+
+// newGPUStackChatModel 对话使用 /v1-openai 下的 OpenAI 兼容接口
+func newGPUStackChatModel(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
+	cfg := buildOpenAIChatConfig(md)
+	cfg.BaseURL = strings.TrimSuffix(gpustackURL(md.BaseURL, gpustackOpenAIPath), "#")
+	return openai.NewChatModel(ctx, cfg)
+}
+
+func newGPUStackEmbedder(ctx context.Context, md *domain.ModelMetadata) (embedding.Embedder, error) {
+	return openaiEmb.NewEmbedder(ctx, &openaiEmb.EmbeddingConfig{
+		APIKey:     md.APIKey,
+		Model:      md.ModelName,
+		BaseURL:    strings.TrimSuffix(gpustackURL(md.BaseURL, gpustackOpenAIPath), "#"),
+		Dimensions: md.EmbedderParam.Dimension,
+	})
+}
+
+// newGPUStackReranker GPUStack 的 /v1/rerank 与 Jina 格式兼容
+func newGPUStackReranker(ctx context.Context, md *domain.ModelMetadata) domain.Reranker {
+	return baaiReranker.NewReranker(ctx, baaiReranker.RerankerConfig{
+		Model:   md.ModelName,
+		BaseUrl: gpustackURL(md.BaseURL, gpustackRerankPath),
+		APIKey:  md.APIKey,
+	})
+}
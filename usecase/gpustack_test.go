@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+// gpustackRoutes GPUStack 的管理接口在 /v1, OpenAI 兼容接口在 /v1-openai
+var gpustackRoutes = standin.Routes{
+	"GET /v1/models": standin.JSON(`{"items":[
+		{"id":1,"name":"qwen2.5-vl-7b","categories":["llm"],"backend":"vllm","backend_version":"0.8.5"},
+		{"id":2,"name":"bge-m3","categories":["embedding"],"backend":"llama-box"},
+		{"id":3,"name":"bge-reranker-v2-m3","categories":["reranker"],"backend":"llama-box"},
+		{"id":4,"name":"stable-diffusion-v3-5-medium","categories":["image"],"backend":"llama-box"}],
+		"pagination":{"page":1,"perPage":100,"total":4,"totalPage":1}}`),
+	"POST /v1-openai/embeddings":       standin.JSON(`{"object":"list","model":"bge-m3","data":[{"object":"embedding","index":0,"embedding":[0.1,0.2]}],"usage":{"prompt_tokens":3,"total_tokens":3}}`),
+	"POST /v1-openai/chat/completions": standin.JSON(`{"id":"c1","object":"chat.completion","model":"qwen2.5-vl-7b","choices":[{"index":0,"message":{"role":"assistant","content":"pong"},"finish_reason":"stop"}]}`),
+	"POST /v1/rerank":                  standin.JSON(`{"model":"bge-reranker-v2-m3","results":[{"index":1,"relevance_score":0.9,"document":{"text":"b"}},{"index":0,"relevance_score":0.1,"document":{"text":"a"}}],"usage":{"prompt_tokens":8,"total_tokens":8}}`),
+}
+
+func TestGPUStackURL(t *testing.T) {
+	cases := []struct {
+		baseURL string
+		apiPath string
+		want    string
+	}{
+		{"http://gpustack:8080/v1", gpustackOpenAIPath, "http://gpustack:8080/v1-openai"},
+		{"http://gpustack:8080/v1-openai/", gpustackRerankPath, "http://gpustack:8080/v1/rerank"},
+		{"http://gpustack:8080", gpustackManagementPath, "http://gpustack:8080/v1"},
+		{"https://proxy.example.com/gpustack/v1-openai", gpustackOpenAIPath, "https://proxy.example.com/gpustack/v1-openai"},
+		// v1 只按完整路径段匹配
+		{"https://proxy.example.com/v1proxy/v1", gpustackRerankPath, "https://proxy.example.com/v1proxy/v1/rerank"},
+		{"https://proxy.example.com/v1-gpu", gpustackOpenAIPath, "https://proxy.example.com/v1-gpu/v1-openai"},
+		{"http://gpustack:8080/custom/chat#", gpustackOpenAIPath, "http://gpustack:8080/custom/chat#"},
+	}
+	for _, tc := range cases {
+		if got := gpustackURL(tc.baseURL, tc.apiPath); got != tc.want {
+			t.Errorf("gpustackURL(%q, %q) = %q, want %q", tc.baseURL, tc.apiPath, got, tc.want)
+		}
+	}
+}
+
+func TestGetChatModel_GPUStack(t *testing.T) {
+	ts := standin.New(t, gpustackRoutes, standin.Header("Authorization", "Bearer gpustack_key"))
+
+	ctx := context.Background()
+	cm, err := NewModelKit(nil).GetChatModel(ctx, &domain.ModelMetadata{
+		Provider:  consts.ModelProviderGPUStack,
+		ModelName: "qwen2.5-vl-7b",
+		BaseURL:   ts.URL + "/v1",
+		APIKey:    "gpustack_key",
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	msg, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("ping")})
+	if err != nil || msg.Content != "pong" {
+		t.Fatalf("Generate failed: %v %+v", err, msg)
+	}
+}
+
+func TestModelList_GPUStackCategories(t *testing.T) {
+	ts := standin.New(t, gpustackRoutes, standin.Header("Authorization", "Bearer gpustack_key"))
+
+	mk := NewModelKit(nil)
+	for typ, want := range map[string]string{
+		"llm":         "qwen2.5-vl-7b",
+		"analysis-vl": "qwen2.5-vl-7b",
+		"embedding":   "bge-m3",
+		"rerank":      "bge-reranker-v2-m3",
+	} {
+		resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+			Provider: string(consts.ModelProviderGPUStack),
+			BaseURL:  ts.URL + "/v1-openai",
+			APIKey:   "gpustack_key",
+			Type:     typ,
+		})
+		if err != nil || resp.Error != "" {
+			t.Fatalf("ModelList failed: %v %+v", err, resp)
+		}
+		if len(resp.Models) != 1 || resp.Models[0].Model != want {
+			t.Fatalf("unexpected %s models: %+v", typ, resp.Models)
+		}
+		if typ == "llm" && resp.Models[0].Backend != "vllm" {
+			t.Fatalf("unexpected backend: %+v", resp.Models[0])
+		}
+	}
+}
+
+func TestGPUStackEmbedderAndReranker(t *testing.T) {
+	ts := standin.New(t, gpustackRoutes, standin.Header("Authorization", "Bearer gpustack_key"))
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	embedder, err := mk.GetEmbedder(ctx, &domain.ModelMetadata{
+		Provider:  consts.ModelProviderGPUStack,
+		ModelName: "bge-m3",
+		BaseURL:   ts.URL + "/v1",
+		APIKey:    "gpustack_key",
+	})
+	if err != nil {
+		t.Fatalf("GetEmbedder failed: %v", err)
+	}
+	emb, err := mk.UseEmbedder(ctx, embedder, []string{"a"})
+	if err != nil || len(emb.Embeddings) != 1 || len(emb.Embeddings[0].Embedding) != 2 {
+		t.Fatalf("UseEmbedder failed: %v %+v", err, emb)
+	}
+
+	rk, err := mk.GetReranker(ctx, &domain.ModelMetadata{
+		Provider:  consts.ModelProviderGPUStack,
+		ModelName: "bge-reranker-v2-m3",
+		BaseURL:   ts.URL + "/v1-openai",
+		APIKey:    "gpustack_key",
+	})
+	if err != nil {
+		t.Fatalf("GetReranker failed: %v", err)
+	}
+	resp, err := rk.Rerank(ctx, domain.RerankRequest{Query: "q", Documents: []string{"a", "b"}, ReturnDocuments: true})
+	if err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Index != 1 || resp.Results[0].Document != "b" {
+		t.Fatalf("unexpected rerank result: %+v", resp)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"runtime"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/cloudwego/eino-ext/components/model/deepseek"
 	"github.com/cloudwego/eino-ext/components/model/gemini"
@@ -23,17 +24,25 @@ import (
 	"github.com/cloudwego/eino-ext/components/model/openai"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
-	generativeGenai "github.com/google/generative-ai-go/genai"
 	"github.com/ollama/ollama/api"
-	"google.golang.org/api/option"
 	"google.golang.org/genai"
 
+	"github.com/chaitin/ModelKit/v2/components/embedder/tei"
+	"github.com/chaitin/ModelKit/v2/components/model/anthropic"
 	"github.com/chaitin/ModelKit/v2/consts"
 	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/pkg/extrabody"
 	"github.com/chaitin/ModelKit/v2/pkg/request"
 	"github.com/chaitin/ModelKit/v2/utils"
 )
 
+const (
+	// ollamaShowConcurrency 并发调用 /api/show 的上限
+	ollamaShowConcurrency = 4
+	// Anthropic extended thinking 要求的最小思考预算
+	anthropicMinThinkingBudget = 1024
+)
+
 // 以下是辅助函数，用于处理模型列表和检查相关的功能
 func ollamaListModel(baseURL string, httpClient *http.Client, apiHeader string) (*domain.ModelListResp, error) {
 	// get from ollama http://10.10.16.24:11434/api/tags
@@ -49,10 +58,36 @@ func ollamaListModel(baseURL string, httpClient *http.Client, apiHeader string)
 		headers := request.GetHeaderMap(apiHeader)
 		maps.Copy(h, headers)
 	}
-	return request.Get[domain.ModelListResp](client, u.Path, request.WithHeader(h))
+	tags, err := request.Get[domain.OllamaTagsResp](client, u.Path, request.WithHeader(h))
+	if err != nil {
+		return nil, err
+	}
+	models := tags.ParseModels()
+
+	// /api/show 补充上下文长度、向量维度与能力, 失败时保留 /api/tags 的信息
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, ollamaShowConcurrency)
+	for i := range models {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item *domain.ModelListItem) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			show, err := request.Post[domain.OllamaShowResp](client, "/api/show", map[string]string{"model": item.Model}, request.WithHeader(h))
+			if err != nil {
+				return
+			}
+			show.Fill(item)
+		}(&models[i])
+	}
+	wg.Wait()
+	return &domain.ModelListResp{Models: models}, nil
 }
 
-func (m *ModelKit) getChatModelGenerateChat(ctx context.Context, provider consts.ModelProvider, modelType consts.ModelType, baseURL string, req *domain.CheckModelReq) (string, error) {
+// checkChatMetadata 根据检测请求构建对话模型参数
+func checkChatMetadata(provider consts.ModelProvider, modelType consts.ModelType, baseURL string, req *domain.CheckModelReq) *domain.ModelMetadata {
 	md := &domain.ModelMetadata{
 		Provider:   provider,
 		ModelName:  req.Model,
@@ -61,14 +96,34 @@ func (m *ModelKit) getChatModelGenerateChat(ctx context.Context, provider consts
 		BaseURL:    baseURL,
 		APIVersion: req.APIVersion,
 		ModelType:  modelType,
+
+		AWSAccessKeyID:     req.AWSAccessKeyID,
+		AWSSecretAccessKey: req.AWSSecretAccessKey,
+		AWSSessionToken:    req.AWSSessionToken,
+		AWSRegion:          req.AWSRegion,
+
+		VertexProject:        req.VertexProject,
+		VertexLocation:       req.VertexLocation,
+		VertexServiceAccount: req.VertexServiceAccount,
+
+		ChatAPI:   consts.ChatAPI(req.ChatAPI),
+		ExtraBody: req.ExtraBody,
 	}
 
 	if req.Param != nil {
 		if req.Param.Temperature != nil {
 			md.Temperature = req.Param.Temperature
 		}
+		if req.Param.R1Enabled {
+			enable := true
+			md.EnableThinking = &enable
+		}
 	}
+	return md
+}
 
+func (m *ModelKit) getChatModelGenerateChat(ctx context.Context, provider consts.ModelProvider, modelType consts.ModelType, baseURL string, req *domain.CheckModelReq) (string, error) {
+	md := checkChatMetadata(provider, modelType, baseURL, req)
 	chatModel, err := m.GetChatModel(ctx, md)
 	if err != nil {
 		return "", err
@@ -77,7 +132,7 @@ func (m *ModelKit) getChatModelGenerateChat(ctx context.Context, provider consts
 
 	// gemini 多模态检测
 	if provider == consts.ModelProviderGemini && checkImage {
-		resp, err := m.geminiImageCheck(ctx, req)
+		resp, err := m.geminiImageCheck(ctx, md)
 		if err != nil {
 			return "", err
 		}
@@ -228,13 +283,18 @@ func reqModelListApi[T domain.ModelResponseParser](req *domain.ModelListReq, htt
 	if err != nil {
 		return nil, err
 	}
+	header := request.Header{
+		"Authorization": fmt.Sprintf("Bearer %s", req.APIKey),
+	}
+	if consts.ParseModelProvider(req.Provider) == consts.ModelProviderAnthropic {
+		header = request.Header{
+			"x-api-key":         req.APIKey,
+			"anthropic-version": anthropic.DefaultAPIVersion,
+		}
+	}
 	resp, err := request.Get[T](
 		client, u.Path,
-		request.WithHeader(
-			request.Header{
-				"Authorization": fmt.Sprintf("Bearer %s", req.APIKey),
-			},
-		),
+		request.WithHeader(header),
 		request.WithQuery(query),
 	)
 	if err != nil {
@@ -299,10 +359,9 @@ func generateBaseURLFixSuggestion(errContent string, baseURL string, provider co
 	}
 }
 
-func (m *ModelKit) geminiImageCheck(ctx context.Context, req *domain.CheckModelReq) (string, error) {
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey: req.APIKey,
-	})
+// geminiImageCheck 与对话检测使用同一个客户端配置(中转地址、请求头、代理与 ExtraBody)
+func (m *ModelKit) geminiImageCheck(ctx context.Context, md *domain.ModelMetadata) (string, error) {
+	client, err := newGeminiClient(ctx, md)
 	if err != nil {
 		return "", err
 	}
@@ -327,7 +386,7 @@ func (m *ModelKit) geminiImageCheck(ctx context.Context, req *domain.CheckModelR
 
 	result, err := client.Models.GenerateContent(
 		ctx,
-		req.Model,
+		md.ModelName,
 		contents,
 		nil,
 	)
@@ -341,18 +400,75 @@ func FilterModelsByType(models []domain.ModelListItem, req *domain.ModelListReq)
 	p := strings.ToLower(req.Provider)
 	t := normalizeType(strings.ToLower(req.Type))
 	pred := modelPredicate(t, p)
-	if pred == nil {
-		return models
-	}
 	filtered := make([]domain.ModelListItem, 0, len(models))
 	for _, it := range models {
-		if pred(it.Model) {
+		// 服务端声明的类别与能力优先, 否则按模型名称匹配
+		if ok, known := matchCategories(t, it); known {
+			if ok {
+				filtered = append(filtered, it)
+			}
+			continue
+		}
+		if ok, known := matchCapabilities(t, it); known {
+			if ok {
+				filtered = append(filtered, it)
+			}
+			continue
+		}
+		name := it.Model
+		if it.BaseModel != "" {
+			name = it.BaseModel
+		}
+		if pred == nil || pred(name) {
 			filtered = append(filtered, it)
 		}
 	}
 	return filtered
 }
 
+// matchCategories 根据服务端声明的类别判断类型, 类别只区分大类, 视觉与代码模型仍需继续判断
+func matchCategories(t string, item domain.ModelListItem) (ok bool, known bool) {
+	if len(item.Categories) == 0 {
+		return false, false
+	}
+	has := func(c string) bool { return slices.Contains(item.Categories, c) }
+	switch t {
+	case "chat", "analysis", "function_call":
+		return has(domain.GPUStackCategoryLLM), true
+	case "embedding":
+		return has(domain.GPUStackCategoryEmbedding), true
+	case "rerank":
+		return has(domain.GPUStackCategoryReranker), true
+	case "analysis-vl", "code":
+		if !has(domain.GPUStackCategoryLLM) {
+			return false, true
+		}
+		return false, false
+	default:
+		return false, false
+	}
+}
+
+// matchCapabilities 根据模型能力判断类型, known 为 false 表示无法据此判断
+func matchCapabilities(t string, item domain.ModelListItem) (ok bool, known bool) {
+	if len(item.Capabilities) == 0 {
+		return false, false
+	}
+	has := func(c string) bool { return slices.Contains(item.Capabilities, c) }
+	switch t {
+	case "chat", "analysis":
+		return has(consts.ModelCapabilityCompletion) && !has(consts.ModelCapabilityEmbedding), true
+	case "analysis-vl":
+		return has(consts.ModelCapabilityVision), true
+	case "embedding":
+		return has(consts.ModelCapabilityEmbedding), true
+	case "function_call":
+		return has(consts.ModelCapabilityTools), true
+	default:
+		return false, false
+	}
+}
+
 func normalizeType(t string) string {
 	switch t {
 	case "chat", "llm":
@@ -508,6 +624,11 @@ func (m *ModelKit) checkChatModel(ctx context.Context, req *domain.CheckModelReq
 	req.BaseURL = strings.TrimSuffix(req.BaseURL, "#")
 	provider := consts.ParseModelProvider(req.Provider)
 	modelType := consts.ParseModelType(req.Type)
+	if err := extrabody.Validate(req.ExtraBody); err != nil {
+		checkResp.Error = err.Error()
+		return checkResp, nil
+	}
+	checkResp.Warnings = m.ValidateChatParams(checkChatMetadata(provider, modelType, req.BaseURL, req)).Warnings
 
 	resp, err := m.getChatModelGenerateChat(ctx, provider, modelType, req.BaseURL, req)
 	if err != nil && (provider == consts.ModelProviderOther || provider == consts.ModelProviderOllama || provider == consts.ModelProviderAzureOpenAI) {
@@ -555,13 +676,28 @@ func (m *ModelKit) checkEmbeddingModel(ctx context.Context, req *domain.CheckMod
 		EmbedderParam: domain.EmbedderParam{
 			EncodingFormat: &encodingFormat,
 		},
+
+		AWSAccessKeyID:     req.AWSAccessKeyID,
+		AWSSecretAccessKey: req.AWSSecretAccessKey,
+		AWSSessionToken:    req.AWSSessionToken,
+		AWSRegion:          req.AWSRegion,
+
+		VertexProject:        req.VertexProject,
+		VertexLocation:       req.VertexLocation,
+		VertexServiceAccount: req.VertexServiceAccount,
 	})
 	if err != nil {
 		checkResp.Error = err.Error()
 		return checkResp, nil
 	}
 
-	embResp, err := m.UseEmbedder(ctx, embedder, []string{"ModelKit 一个轻量级工具库，提供 AI 模型发现与 API 密钥验证功能，助你快速集成各大模型供应商能力。"})
+	var embResp *domain.EmbeddingsResponse
+	if req.Param != nil && req.Param.SupportImages {
+		// 多模态向量检测, 图文融合输入需要返回向量
+		embResp, err = m.UseMultimodalEmbedder(ctx, embedder, []domain.EmbeddingInput{{Text: "一只狗", ImageURL: consts.ImageBase64}})
+	} else {
+		embResp, err = m.UseEmbedder(ctx, embedder, []string{"ModelKit 一个轻量级工具库，提供 AI 模型发现与 API 密钥验证功能，助你快速集成各大模型供应商能力。"})
+	}
 	if err != nil {
 		checkResp.Error = err.Error()
 		return checkResp, nil
@@ -570,8 +706,13 @@ func (m *ModelKit) checkEmbeddingModel(ctx context.Context, req *domain.CheckMod
 		checkResp.Error = "empty embeddings"
 		return checkResp, nil
 	}
-	dim := len(embResp.Embeddings[0].Embedding)
-	checkResp.Content = fmt.Sprintf("dim is : %d", dim)
+	item := embResp.Embeddings[0]
+	// 多向量输出没有单一向量, 报告每个 token 向量的维度与向量个数
+	if len(item.Embedding) == 0 && len(item.MultiEmbedding) > 0 {
+		checkResp.Content = fmt.Sprintf("dim is : %d, vectors: %d", len(item.MultiEmbedding[0]), len(item.MultiEmbedding))
+		return checkResp, nil
+	}
+	checkResp.Content = fmt.Sprintf("dim is : %d", len(item.Embedding))
 	return checkResp, nil
 }
 
@@ -580,10 +721,14 @@ func (m *ModelKit) checkRerankModel(ctx context.Context, req *domain.CheckModelR
 	provider := consts.ParseModelProvider(req.Provider)
 
 	reranker, err := m.GetReranker(ctx, &domain.ModelMetadata{
-		Provider:  provider,
-		ModelName: req.Model,
-		BaseURL:   req.BaseURL,
-		APIKey:    req.APIKey,
+		Provider:       provider,
+		ModelName:      req.Model,
+		BaseURL:        req.BaseURL,
+		APIKey:         req.APIKey,
+		RerankProtocol: consts.RerankProtocol(req.RerankProtocol),
+
+		VolcengineKnowledgeBaseURL: req.VolcengineKnowledgeBaseURL,
+		VolcengineKnowledgeAPIKey:  req.VolcengineKnowledgeAPIKey,
 	})
 	if err != nil {
 		checkResp.Error = err.Error()
@@ -616,32 +761,23 @@ func (m *ModelKit) listStaticProvider(req *domain.ModelListReq, provider consts.
 	return &domain.ModelListResp{Models: filtered}, nil
 }
 
-func (m *ModelKit) listGemini(ctx context.Context, req *domain.ModelListReq) (*domain.ModelListResp, error) {
-	client, err := generativeGenai.NewClient(ctx, option.WithAPIKey(req.APIKey))
+func (m *ModelKit) listGemini(ctx context.Context, req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
+	client, err := genai.NewClient(ctx, geminiClientConfig(req.APIKey, req.BaseURL, req.APIHeader, httpClient))
 	if err != nil {
 		return &domain.ModelListResp{Error: err.Error()}, nil
 	}
-	defer func() {
-		if closeErr := client.Close(); closeErr != nil {
-			if m.logger != nil {
-				m.logger.Error("Failed to close gemini client: %v", slog.Any("error", closeErr))
-			} else {
-				log.Printf("Failed to close gemini client: %v", closeErr)
-			}
-		}
-	}()
 
 	modelsList := make([]domain.ModelListItem, 0)
-	modelsIter := client.ListModels(ctx)
-	for {
-		model, err := modelsIter.Next()
+	for model, err := range client.Models.All(ctx) {
 		if err != nil {
-			break
+			return &domain.ModelListResp{Error: fmt.Errorf("获取Gemini模型列表失败: %w", err).Error()}, nil
 		}
-		if !slices.Contains(model.SupportedGenerationMethods, "generateContent") {
+		// 向量模型(如 text-embedding-004)只支持 embedContent, 名称中不一定包含 gemini
+		embed := slices.Contains(model.SupportedActions, "embedContent")
+		if !embed && !slices.Contains(model.SupportedActions, "generateContent") {
 			continue
 		}
-		if !strings.Contains(model.Name, "gemini") {
+		if !embed && !strings.Contains(model.Name, "gemini") {
 			continue
 		}
 		name, _ := strings.CutPrefix(model.Name, "models/")
@@ -663,6 +799,15 @@ func (m *ModelKit) listGithub(req *domain.ModelListReq, httpClient *http.Client)
 	return &domain.ModelListResp{Models: filtered}, nil
 }
 
+func (m *ModelKit) listOpenRouter(req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
+	models, err := reqModelListApi(req, httpClient, &domain.OpenRouterResp{})
+	if err != nil {
+		return &domain.ModelListResp{Error: err.Error()}, nil
+	}
+	filtered := FilterModelsByType(models, req)
+	return &domain.ModelListResp{Models: filtered}, nil
+}
+
 func (m *ModelKit) listOllama(req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
 	var modelListResp domain.ModelListResp
 	var err error
@@ -691,23 +836,40 @@ func (m *ModelKit) listOllama(req *domain.ModelListReq, httpClient *http.Client)
 	return &modelListResp, nil
 }
 
-func (m *ModelKit) listGPUStack(req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
-	provider := consts.ParseModelProvider(req.Provider)
-	models, err := reqModelListApi(req, httpClient, &domain.GPUStackListModelResp{})
+func (m *ModelKit) listAnthropic(req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
+	listReq := *req
+	if !strings.HasSuffix(listReq.BaseURL, "#") {
+		listReq.BaseURL = anthropic.NormalizeBaseURL(listReq.BaseURL)
+	}
+	models, err := reqModelListApi(&listReq, httpClient, &domain.AnthropicResp{})
 	if err != nil {
-		if m.logger != nil {
-			m.logger.Error("GPUStack list model failed", "error", err, "models: ", models)
-		}
-		msg := generateBaseURLFixSuggestion(err.Error(), req.BaseURL, provider)
-		if msg == "" {
-			return &domain.ModelListResp{Error: err.Error()}, nil
-		}
-		return &domain.ModelListResp{Error: msg}, nil
+		return &domain.ModelListResp{Error: err.Error()}, nil
 	}
 	filtered := FilterModelsByType(models, req)
 	return &domain.ModelListResp{Models: filtered}, nil
 }
 
+// listTEI TEI 每个实例只部署一个模型, 从 /info 读取模型ID与类型
+func (m *ModelKit) listTEI(req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
+	u, err := url.Parse(tei.NormalizeBaseURL(req.BaseURL))
+	if err != nil {
+		return &domain.ModelListResp{Error: err.Error()}, nil
+	}
+	client := request.NewClient(u.Scheme, u.Host, httpClient.Timeout, request.WithClient(httpClient))
+	var opts []request.Opt
+	if req.APIKey != "" {
+		opts = append(opts, request.WithHeader(request.Header{"Authorization": "Bearer " + req.APIKey}))
+	}
+	info, err := request.Get[domain.TEIInfo](client, path.Join(u.Path, "/info"), opts...)
+	if err != nil {
+		return &domain.ModelListResp{Error: err.Error()}, nil
+	}
+	if info.Type() != consts.ParseModelType(req.Type) {
+		return &domain.ModelListResp{Models: []domain.ModelListItem{}}, nil
+	}
+	return &domain.ModelListResp{Models: info.ParseModels()}, nil
+}
+
 func (m *ModelKit) listOpenAI(req *domain.ModelListReq, httpClient *http.Client, provider consts.ModelProvider) (*domain.ModelListResp, error) {
 	models, err := reqModelListApi(req, httpClient, &domain.OpenAIResp{})
 	if err != nil {
@@ -761,23 +923,30 @@ func buildOpenAIChatConfig(md *domain.ModelMetadata) *openai.ChatModelConfig {
 	if md.LogitBias != nil {
 		cfg.LogitBias = md.LogitBias
 	}
+	if fields := openAIThinkingFields(md); len(fields) > 0 {
+		cfg.ExtraFields = fields
+	}
 	if md.Provider == consts.ModelProviderAzureOpenAI {
 		cfg.ByAzure = true
 		cfg.APIVersion = md.APIVersion
 		if cfg.APIVersion == "" {
-			cfg.APIVersion = "2024-10-21"
+			cfg.APIVersion = azureDefaultAPIVersion
 		}
 		cfg.AzureModelMapperFunc = func(model string) string {
 			return model
 		}
 	}
+	cfg.HTTPClient = chatHTTPClient(md)
+	return cfg
+}
+
+// chatHTTPClient 对话模型使用的客户端, 附加 APIHeader 并合并 ExtraBody
+func chatHTTPClient(md *domain.ModelMetadata) *http.Client {
+	var hc *http.Client
 	if md.APIHeader != "" {
-		hc := utils.GetHttpClientWithAPIHeaderMap(md.APIHeader)
-		if hc != nil {
-			cfg.HTTPClient = hc
-		}
+		hc = utils.GetHttpClientWithAPIHeaderMap(md.APIHeader)
 	}
-	return cfg
+	return extrabody.WrapClient(hc, md.ExtraBody)
 }
 
 func shouldIgnoreOpenAITemperature(model string) bool {
@@ -787,6 +956,39 @@ func shouldIgnoreOpenAITemperature(model string) bool {
 		strings.HasPrefix(model, "gpt-5")
 }
 
+// openAIThinkingFields 百炼、硅基流动通过 enable_thinking 开关思考, 支持的 OpenAI 兼容接口使用 reasoning_effort
+func openAIThinkingFields(md *domain.ModelMetadata) map[string]any {
+	fields := map[string]any{}
+	switch md.Provider {
+	case consts.ModelProviderBaiLian, consts.ModelProviderSiliconFlow:
+		if md.EnableThinking != nil {
+			fields["enable_thinking"] = *md.EnableThinking
+		}
+		if md.ThinkingBudget != nil {
+			fields["thinking_budget"] = *md.ThinkingBudget
+		}
+	default:
+		if md.ReasoningEffort != "" && slices.Contains(reasoningEffortProviders, md.Provider) {
+			fields["reasoning_effort"] = md.ReasoningEffort
+		}
+	}
+	return fields
+}
+
+// deepseekModelName deepseek-chat 与 deepseek-reasoner 分别是同一模型的非思考与思考模式
+func deepseekModelName(md *domain.ModelMetadata) string {
+	if md.EnableThinking == nil {
+		return md.ModelName
+	}
+	switch {
+	case *md.EnableThinking && md.ModelName == "deepseek-chat":
+		return "deepseek-reasoner"
+	case !*md.EnableThinking && md.ModelName == "deepseek-reasoner":
+		return "deepseek-chat"
+	}
+	return md.ModelName
+}
+
 func newDeepseekChatModel(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
 	t := float32(0.0)
 	if md.Temperature != nil {
@@ -795,8 +997,9 @@ func newDeepseekChatModel(ctx context.Context, md *domain.ModelMetadata) (model.
 	cfg := &deepseek.ChatModelConfig{
 		BaseURL:     md.BaseURL,
 		APIKey:      md.APIKey,
-		Model:       md.ModelName,
+		Model:       deepseekModelName(md),
 		Temperature: t,
+		HTTPClient:  chatHTTPClient(md),
 	}
 	if md.MaxTokens != nil {
 		cfg.MaxTokens = *md.MaxTokens
@@ -813,21 +1016,103 @@ func newDeepseekChatModel(ctx context.Context, md *domain.ModelMetadata) (model.
 	if md.FrequencyPenalty != nil {
 		cfg.FrequencyPenalty = *md.FrequencyPenalty
 	}
-	return deepseek.NewChatModel(ctx, cfg)
+	// DeepSeek 只支持 json_object, json_schema 通过提示词约束结构
+	prompt, err := jsonPrompt(md)
+	if err != nil {
+		return nil, err
+	}
+	if prompt != "" {
+		cfg.ResponseFormatType = deepseek.ResponseFormatTypeJSONObject
+	}
+	cm, err := deepseek.NewChatModel(ctx, cfg)
+	if err != nil || prompt == "" {
+		return cm, err
+	}
+	return &jsonPromptChatModel{inner: cm, prompt: prompt}, nil
 }
 
 func newGeminiChatModel(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{APIKey: md.APIKey})
+	client, err := newGeminiClient(ctx, md)
+	if err != nil {
+		return nil, err
+	}
+	return newGeminiChatModelWithClient(ctx, client, md)
+}
+
+// newGeminiClient 对话与图片检测共用的 Gemini API 客户端
+func newGeminiClient(ctx context.Context, md *domain.ModelMetadata) (*genai.Client, error) {
+	cfg := geminiClientConfig(md.APIKey, md.BaseURL, md.APIHeader, extrabody.WrapClient(md.HTTPClient, md.ExtraBody))
+	cfg.HTTPOptions.ExtraBody = geminiExtraBody(md)
+	return genai.NewClient(ctx, cfg)
+}
+
+// geminiVersionRe 匹配 BaseURL 末尾的 API 版本, 例如 v1、v1beta、v1alpha
+var geminiVersionRe = regexp.MustCompile(`^v\d+(?:alpha|beta)?\d*$`)
+
+// geminiClientConfig 支持中转地址、自定义请求头与 HTTP 客户端(代理), BaseURL 末尾的版本号拆分为 APIVersion.
+// 误填的 OpenAI 兼容地址(如 .../v1beta/openai/)去掉末尾的 /openai 后按原生接口访问
+func geminiClientConfig(apiKey, baseURL, apiHeader string, httpClient *http.Client) *genai.ClientConfig {
+	cfg := &genai.ClientConfig{
+		APIKey:     apiKey,
+		Backend:    genai.BackendGeminiAPI,
+		HTTPClient: httpClient,
+	}
+	if u, err := url.Parse(strings.TrimSuffix(baseURL, "#")); err == nil && u.Host != "" {
+		p := strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/openai")
+		if i := strings.LastIndex(p, "/"); i >= 0 && geminiVersionRe.MatchString(p[i+1:]) {
+			cfg.HTTPOptions.APIVersion = p[i+1:]
+			p = p[:i]
+		}
+		u.Path = p + "/"
+		u.RawPath = ""
+		cfg.HTTPOptions.BaseURL = u.String()
+	}
+	if apiHeader != "" {
+		cfg.HTTPOptions.Headers = http.Header{}
+		for k, v := range request.GetHeaderMap(apiHeader) {
+			cfg.HTTPOptions.Headers.Set(k, v)
+		}
+	}
+	return cfg
+}
+
+// geminiEmbedderBaseURL 按 geminiClientConfig 的规则解析 BaseURL, 对话与向量模型可以填写同一地址,
+// 未指定版本时使用 v1beta, 以 # 结尾时原样交给向量组件
+func geminiEmbedderBaseURL(md *domain.ModelMetadata) string {
+	if md.BaseURL == "" || strings.HasSuffix(md.BaseURL, "#") {
+		return md.BaseURL
+	}
+	opts := geminiClientConfig(md.APIKey, md.BaseURL, md.APIHeader, md.HTTPClient).HTTPOptions
+	if opts.BaseURL == "" {
+		return md.BaseURL
+	}
+	version := opts.APIVersion
+	if version == "" {
+		version = "v1beta"
+	}
+	return opts.BaseURL + version
+}
+
+// newGeminiChatModelWithClient Gemini API 与 Vertex AI 共用同一套对话参数, 客户端需已设置 geminiExtraBody
+func newGeminiChatModelWithClient(ctx context.Context, client *genai.Client, md *domain.ModelMetadata) (model.BaseChatModel, error) {
+	thinkingConfig, err := geminiThinkingConfig(geminiParam(md))
+	if err != nil {
+		return nil, err
+	}
+	safetySettings, err := geminiSafetySettings(md.GeminiParam)
+	if err != nil {
+		return nil, err
+	}
+	responseSchema, err := geminiResponseJSONSchema(md)
 	if err != nil {
 		return nil, err
 	}
 	cfg := &gemini.Config{
-		Client: client,
-		Model:  md.ModelName,
-		ThinkingConfig: &genai.ThinkingConfig{
-			IncludeThoughts: true,
-			ThinkingBudget:  nil,
-		},
+		Client:             client,
+		Model:              md.ModelName,
+		ThinkingConfig:     thinkingConfig,
+		SafetySettings:     safetySettings,
+		ResponseJSONSchema: responseSchema,
 	}
 	if md.MaxTokens != nil {
 		cfg.MaxTokens = md.MaxTokens
@@ -841,6 +1126,43 @@ func newGeminiChatModel(ctx context.Context, md *domain.ModelMetadata) (model.Ba
 	return gemini.NewChatModel(ctx, cfg)
 }
 
+func newAnthropicChatModel(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
+	cfg := &anthropic.ChatModelConfig{
+		APIKey:      md.APIKey,
+		Model:       md.ModelName,
+		BaseURL:     md.BaseURL,
+		MaxTokens:   md.MaxTokens,
+		Temperature: md.Temperature,
+		TopP:        md.TopP,
+		// 通过强制调用输出工具实现结构化输出
+		ResponseSchema: responseSchema(md),
+	}
+	if len(md.Stop) > 0 {
+		cfg.StopSequences = md.Stop
+	}
+	cfg.ThinkingBudget = anthropicThinkingBudget(md)
+	cfg.HTTPClient = chatHTTPClient(md)
+	return anthropic.NewChatModel(ctx, cfg)
+}
+
+// anthropicThinkingBudget 开启思考且未设置预算时使用最小预算, 返回 nil 表示不开启 extended thinking
+func anthropicThinkingBudget(md *domain.ModelMetadata) *int {
+	if md.EnableThinking != nil && !*md.EnableThinking {
+		return nil
+	}
+	if md.ThinkingBudget != nil {
+		if *md.ThinkingBudget <= 0 {
+			return nil
+		}
+		return md.ThinkingBudget
+	}
+	if md.EnableThinking != nil {
+		budget := anthropicMinThinkingBudget
+		return &budget
+	}
+	return nil
+}
+
 func newOllamaChatModel(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
 	if strings.HasSuffix(md.BaseURL, "/v1") {
 		cfg := buildOpenAIChatConfig(md)
@@ -870,5 +1192,33 @@ func newOllamaChatModel(ctx context.Context, md *domain.ModelMetadata) (model.Ba
 	if md.Seed != nil {
 		opts.Seed = *md.Seed
 	}
-	return ollama.NewChatModel(ctx, &ollama.ChatModelConfig{BaseURL: baseUrl, Model: string(md.ModelName), Options: opts})
+	format, err := ollamaFormat(md)
+	if err != nil {
+		return nil, err
+	}
+	return ollama.NewChatModel(ctx, &ollama.ChatModelConfig{
+		BaseURL:    baseUrl,
+		HTTPClient: chatHTTPClient(md),
+		Model:      string(md.ModelName),
+		Format:     format,
+		Options:    opts,
+		Thinking:   ollamaThinkValue(md),
+	})
+}
+
+// ollamaThinkValue think 支持布尔值, gpt-oss 等模型还支持 low、medium、high 等级
+func ollamaThinkValue(md *domain.ModelMetadata) *api.ThinkValue {
+	if md.EnableThinking != nil && !*md.EnableThinking {
+		return &api.ThinkValue{Value: false}
+	}
+	switch md.ReasoningEffort {
+	case "minimal", "low":
+		return &api.ThinkValue{Value: "low"}
+	case "medium", "high":
+		return &api.ThinkValue{Value: md.ReasoningEffort}
+	}
+	if md.EnableThinking != nil {
+		return &api.ThinkValue{Value: true}
+	}
+	return nil
 }
@@ -3,12 +3,15 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/cloudwego/eino/schema"
+
 	"github.com/chaitin/ModelKit/v2/consts"
 	"github.com/chaitin/ModelKit/v2/domain"
 )
@@ -77,6 +80,85 @@ func TestBuildOpenAIChatConfig_IgnoreTemperatureForReasoningModels(t *testing.T)
 	}
 }
 
+func TestBuildOpenAIChatConfig_Thinking(t *testing.T) {
+	enable, budget := true, 2048
+	testCases := []struct {
+		provider consts.ModelProvider
+		want     map[string]any
+	}{
+		{provider: consts.ModelProviderOpenAI, want: map[string]any{"reasoning_effort": "high"}},
+		{provider: consts.ModelProviderAzureOpenAI, want: map[string]any{"reasoning_effort": "high"}},
+		{provider: consts.ModelProviderBaiLian, want: map[string]any{"enable_thinking": true, "thinking_budget": 2048}},
+		{provider: consts.ModelProviderSiliconFlow, want: map[string]any{"enable_thinking": true, "thinking_budget": 2048}},
+		{provider: consts.ModelProviderZhiPu, want: map[string]any{}},
+		{provider: consts.ModelProviderMoonshot, want: map[string]any{}},
+	}
+	for _, tc := range testCases {
+		cfg := buildOpenAIChatConfig(&domain.ModelMetadata{
+			Provider:        tc.provider,
+			ModelName:       "qwen3-32b",
+			ReasoningEffort: "high",
+			EnableThinking:  &enable,
+			ThinkingBudget:  &budget,
+		})
+		if len(cfg.ExtraFields) != len(tc.want) {
+			t.Fatalf("%s: unexpected extra fields: %v", tc.provider, cfg.ExtraFields)
+		}
+		for k, v := range tc.want {
+			if cfg.ExtraFields[k] != v {
+				t.Fatalf("%s: unexpected extra fields: %v", tc.provider, cfg.ExtraFields)
+			}
+		}
+	}
+	if cfg := buildOpenAIChatConfig(&domain.ModelMetadata{ModelName: "gpt-4.1"}); cfg.ExtraFields != nil {
+		t.Fatalf("expected no extra fields, got %v", cfg.ExtraFields)
+	}
+}
+
+func TestThinkingMapping(t *testing.T) {
+	on, off, budget := true, false, 512
+
+	if got := deepseekModelName(&domain.ModelMetadata{ModelName: "deepseek-chat", EnableThinking: &on}); got != "deepseek-reasoner" {
+		t.Fatalf("unexpected deepseek model: %s", got)
+	}
+	if got := deepseekModelName(&domain.ModelMetadata{ModelName: "deepseek-reasoner", EnableThinking: &off}); got != "deepseek-chat" {
+		t.Fatalf("unexpected deepseek model: %s", got)
+	}
+
+	for _, tc := range []struct {
+		md   *domain.ModelMetadata
+		want any
+	}{
+		{md: &domain.ModelMetadata{}, want: nil},
+		{md: &domain.ModelMetadata{EnableThinking: &on}, want: true},
+		{md: &domain.ModelMetadata{EnableThinking: &off, ReasoningEffort: "high"}, want: false},
+		{md: &domain.ModelMetadata{ReasoningEffort: "minimal"}, want: "low"},
+		{md: &domain.ModelMetadata{EnableThinking: &on, ReasoningEffort: "medium"}, want: "medium"},
+	} {
+		v := ollamaThinkValue(tc.md)
+		if (v == nil) != (tc.want == nil) || (v != nil && v.Value != tc.want) {
+			t.Fatalf("unexpected think value for %+v: %+v", tc.md, v)
+		}
+	}
+
+	if p := geminiParam(&domain.ModelMetadata{EnableThinking: &off, ThinkingBudget: &budget}); p.ThinkingBudget == nil || *p.ThinkingBudget != 0 {
+		t.Fatalf("expected thinking disabled: %+v", p)
+	}
+	if p := geminiParam(&domain.ModelMetadata{ThinkingBudget: &budget}); p.ThinkingBudget == nil || *p.ThinkingBudget != 512 {
+		t.Fatalf("unexpected thinking budget: %+v", p)
+	}
+	if p := geminiParam(&domain.ModelMetadata{ModelName: "gemini-3-pro-preview", ReasoningEffort: "low"}); p.ThinkingLevel != "low" {
+		t.Fatalf("unexpected thinking level: %+v", p)
+	}
+	if p := geminiParam(&domain.ModelMetadata{ModelName: "gemini-2.5-flash", ReasoningEffort: "low"}); p.ThinkingLevel != "" || p.ThinkingBudget != nil {
+		t.Fatalf("expected effort ignored for gemini 2.5: %+v", p)
+	}
+	level := domain.GeminiParam{ThinkingLevel: "high"}
+	if p := geminiParam(&domain.ModelMetadata{GeminiParam: level, EnableThinking: &off}); p.ThinkingBudget != nil || p.ThinkingLevel != "high" {
+		t.Fatalf("expected gemini_param to take precedence: %+v", p)
+	}
+}
+
 func TestCheckModel_TemperaturePassed(t *testing.T) {
 	testName := "TestCheckModel_TemperaturePassed_Provider=Moonshot_Model=kimi-k2.5_Temp=1"
 	// 1. Setup a test server to intercept the request
@@ -154,3 +236,238 @@ func TestCheckModel_TemperaturePassed(t *testing.T) {
 	}
 	t.Logf("pass case: %s; response: %+v", testName, resp)
 }
+
+func TestGetChatModel_AnthropicThinkingMaxTokens(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			MaxTokens int `json:"max_tokens"`
+			Thinking  struct {
+				BudgetTokens int `json:"budget_tokens"`
+			} `json:"thinking"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&reqBody)
+		// max_tokens 必须大于思考预算
+		if reqBody.Thinking.BudgetTokens != 8192 || reqBody.MaxTokens <= reqBody.Thinking.BudgetTokens {
+			t.Errorf("unexpected request: %+v", reqBody)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"ok"}],"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer ts.Close()
+
+	budget := 8192
+	chatModel, err := NewModelKit(nil).GetChatModel(context.Background(), &domain.ModelMetadata{
+		Provider:       consts.ModelProviderAnthropic,
+		ModelName:      "claude-sonnet-4-5",
+		BaseURL:        ts.URL,
+		APIKey:         "sk-ant-test",
+		ThinkingBudget: &budget,
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	if _, err := chatModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("hi")}); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+}
+
+func TestGetChatModel_Anthropic(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "sk-ant-test" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("missing anthropic headers: %v", r.Header)
+		}
+		var reqBody struct {
+			System    string `json:"system"`
+			MaxTokens int    `json:"max_tokens"`
+			Stream    bool   `json:"stream"`
+			Messages  []struct {
+				Role string `json:"role"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+			return
+		}
+		if reqBody.System != "You are a helpful assistant." || reqBody.MaxTokens == 0 || len(reqBody.Messages) != 1 {
+			t.Errorf("unexpected request: %+v", reqBody)
+		}
+
+		if reqBody.Stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			events := []string{
+				`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"usage":{"input_tokens":10,"output_tokens":1}}}`,
+				`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"hmm"}}`,
+				`{"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`,
+				`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Hel"}}`,
+				`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"lo"}}`,
+				`{"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}`,
+				`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`,
+				`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`,
+				`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":7}}`,
+				`{"type":"message_stop"}`,
+			}
+			for _, ev := range events {
+				_, _ = w.Write([]byte("event: x\ndata: " + ev + "\n\n"))
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "msg_1",
+			"type": "message",
+			"role": "assistant",
+			"content": [
+				{"type": "thinking", "thinking": "hmm", "signature": "sig"},
+				{"type": "text", "text": "Hello there!"}
+			],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 10, "output_tokens": 5}
+		}`))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	chatModel, err := mk.GetChatModel(ctx, &domain.ModelMetadata{
+		Provider:  consts.ModelProviderAnthropic,
+		ModelName: "claude-sonnet-4-5",
+		BaseURL:   ts.URL,
+		APIKey:    "sk-ant-test",
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	msgs := []*schema.Message{
+		schema.SystemMessage("You are a helpful assistant."),
+		schema.UserMessage("hi"),
+	}
+
+	resp, err := chatModel.Generate(ctx, msgs)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if resp.Content != "Hello there!" || resp.ReasoningContent != "hmm" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.ResponseMeta.Usage.TotalTokens != 15 {
+		t.Fatalf("unexpected usage: %+v", resp.ResponseMeta.Usage)
+	}
+
+	sr, err := chatModel.Stream(ctx, msgs)
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	full, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		t.Fatalf("ConcatMessages failed: %v", err)
+	}
+	if full.Content != "Hello" || full.ReasoningContent != "hmm" {
+		t.Fatalf("unexpected stream content: %+v", full)
+	}
+	if len(full.ToolCalls) != 1 || full.ToolCalls[0].Function.Name != "get_weather" || full.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Fatalf("unexpected tool calls: %+v", full.ToolCalls)
+	}
+	if full.ResponseMeta.FinishReason != "tool_use" || full.ResponseMeta.Usage.CompletionTokens != 7 {
+		t.Fatalf("unexpected response meta: %+v", full.ResponseMeta)
+	}
+}
+
+func TestModelList_Anthropic(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "sk-ant-test" || r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected auth headers: %v", r.Header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"type":"model","id":"claude-sonnet-4-5","display_name":"Claude Sonnet 4.5"}],"has_more":false}`))
+	}))
+	defer ts.Close()
+
+	mk := NewModelKit(nil)
+	resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+		Provider: string(consts.ModelProviderAnthropic),
+		BaseURL:  ts.URL,
+		APIKey:   "sk-ant-test",
+		Type:     "chat",
+	})
+	if err != nil {
+		t.Fatalf("ModelList failed: %v", err)
+	}
+	if resp.Error != "" || len(resp.Models) != 1 || resp.Models[0].Model != "claude-sonnet-4-5" {
+		t.Fatalf("unexpected model list: %+v", resp)
+	}
+}
+
+func TestGetEmbedder_Gemini(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-goog-api-key") != "gemini-key" {
+			t.Errorf("unexpected api key: %q", r.Header.Get("x-goog-api-key"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1beta/models/gemini-embedding-001:batchEmbedContents":
+			var body struct {
+				Requests []struct {
+					Model                string `json:"model"`
+					TaskType             string `json:"taskType"`
+					OutputDimensionality int    `json:"outputDimensionality"`
+				} `json:"requests"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Requests) != 2 || body.Requests[0].Model != "models/gemini-embedding-001" ||
+				body.Requests[0].TaskType != "RETRIEVAL_QUERY" || body.Requests[1].OutputDimensionality != 768 {
+				t.Errorf("unexpected request: %+v", body)
+			}
+			_, _ = w.Write([]byte(`{"embeddings":[{"values":[0.1,0.2]},{"values":[0.3,0.4]}]}`))
+		case "/v1beta/models/gemini-embedding-001:countTokens":
+			_, _ = w.Write([]byte(`{"totalTokens":7}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	dim := 768
+	textType := "query"
+	countTokens := true
+	// 与对话模型相同的地址写法均可使用
+	for _, baseURL := range []string{ts.URL, ts.URL + "/v1beta", ts.URL + "/v1beta/openai/"} {
+		embedder, err := mk.GetEmbedder(ctx, &domain.ModelMetadata{
+			Provider:      consts.ModelProviderGemini,
+			ModelName:     "gemini-embedding-001",
+			BaseURL:       baseURL,
+			APIKey:        "gemini-key",
+			EmbedderParam: domain.EmbedderParam{Dimension: &dim, TextType: &textType, CountTokens: &countTokens},
+		})
+		if err != nil {
+			t.Fatalf("GetEmbedder failed: %v", err)
+		}
+		resp, err := mk.UseEmbedder(ctx, embedder, []string{"a", "b"})
+		if err != nil {
+			t.Fatalf("%s: UseEmbedder failed: %v", baseURL, err)
+		}
+		if len(resp.Embeddings) != 2 || resp.Embeddings[1].TextIndex != 1 || resp.Embeddings[1].Embedding[0] != 0.3 || resp.Usage.TotalTokens != 7 {
+			t.Fatalf("%s: unexpected embeddings: %+v", baseURL, resp)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+func TestGetEmbedder_Jina(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/embeddings":
+			if body["task"] != "retrieval.query" || body["late_chunking"] != true || body["dimensions"] != float64(256) {
+				t.Errorf("unexpected request: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"data":[{"index":0,"embedding":[0.1,0.2]},{"index":1,"embedding":[0.3,0.4]}],"usage":{"total_tokens":6}}`))
+		case "/v1/multi-vector":
+			if body["input_type"] != "query" || body["task"] != nil {
+				t.Errorf("unexpected request: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"data":[{"index":0,"embeddings":[[0.1,0.2],[0.3,0.4]]}],"usage":{"total_tokens":3}}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	dim := 256
+	textType := "query"
+	lateChunking := true
+	embedder, err := mk.GetEmbedder(ctx, &domain.ModelMetadata{
+		Provider:  consts.ModelProviderJina,
+		ModelName: "jina-embeddings-v3",
+		BaseURL:   ts.URL + "/v1",
+		APIKey:    "jina-key",
+		EmbedderParam: domain.EmbedderParam{
+			Dimension:    &dim,
+			TextType:     &textType,
+			LateChunking: &lateChunking,
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetEmbedder failed: %v", err)
+	}
+	resp, err := mk.UseEmbedder(ctx, embedder, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("UseEmbedder failed: %v", err)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[1].Embedding[0] != 0.3 || resp.Usage.TotalTokens != 6 {
+		t.Fatalf("unexpected embeddings: %+v", resp)
+	}
+
+	colbert, err := mk.GetEmbedder(ctx, &domain.ModelMetadata{
+		Provider:      consts.ModelProviderJina,
+		ModelName:     "jina-colbert-v2",
+		BaseURL:       ts.URL + "/v1",
+		APIKey:        "jina-key",
+		EmbedderParam: domain.EmbedderParam{TextType: &textType},
+	})
+	if err != nil {
+		t.Fatalf("GetEmbedder failed: %v", err)
+	}
+	resp, err = mk.UseEmbedder(ctx, colbert, []string{"a"})
+	if err != nil {
+		t.Fatalf("UseEmbedder failed: %v", err)
+	}
+	if len(resp.Embeddings) != 1 || len(resp.Embeddings[0].MultiEmbedding) != 2 || resp.Embeddings[0].Embedding != nil {
+		t.Fatalf("unexpected multi-vector embeddings: %+v", resp)
+	}
+}
+
+func TestCheckModel_JinaRerank(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/rerank" || r.Header.Get("Authorization") != "Bearer jina-key" {
+			t.Errorf("unexpected request: %s %v", r.URL.Path, r.Header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"jina-reranker-v2-base-multilingual","usage":{"total_tokens":12},"results":[
+			{"index":4,"relevance_score":0.9,"document":{"text":"火鸡"}},
+			{"index":0,"relevance_score":0.8,"document":{"text":"鸡"}}
+		]}`))
+	}))
+	defer ts.Close()
+
+	mk := NewModelKit(nil)
+	resp, err := mk.CheckModel(context.Background(), &domain.CheckModelReq{
+		Provider: string(consts.ModelProviderJina),
+		Model:    "jina-reranker-v2-base-multilingual",
+		BaseURL:  ts.URL + "/v1",
+		APIKey:   "jina-key",
+		Type:     string(consts.ModelTypeRerank),
+	})
+	if err != nil {
+		t.Fatalf("CheckModel failed: %v", err)
+	}
+	if resp.Error != "" || resp.Content != "火鸡\n鸡" {
+		t.Fatalf("unexpected check result: %+v", resp)
+	}
+}
+
+func TestCheckModel_JinaMultiVector(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"POST /v1/multi-vector": standin.JSON(`{"data":[{"index":0,"embeddings":[[0.1,0.2,0.3],[0.4,0.5,0.6]]}],"usage":{"total_tokens":3}}`),
+	}, standin.Header("Authorization", "Bearer jina-key"))
+
+	resp, err := NewModelKit(nil).CheckModel(context.Background(), &domain.CheckModelReq{
+		Provider: string(consts.ModelProviderJina),
+		Model:    "jina-colbert-v2",
+		BaseURL:  ts.URL + "/v1",
+		APIKey:   "jina-key",
+		Type:     string(consts.ModelTypeEmbedding),
+	})
+	if err != nil {
+		t.Fatalf("CheckModel failed: %v", err)
+	}
+	if resp.Error != "" || resp.Content != "dim is : 3, vectors: 2" {
+		t.Fatalf("unexpected check result: %+v", resp)
+	}
+}
+
+func TestGetReranker_JinaHTTPClient(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"POST /v1/rerank": standin.JSON(`{"model":"jina-reranker-v2-base-multilingual","results":[{"index":0,"relevance_score":0.9}]}`),
+	}, standin.Header("Authorization", "Bearer jina-key"))
+
+	transport := &countingTransport{}
+	rk, err := NewModelKit(nil).GetReranker(context.Background(), &domain.ModelMetadata{
+		Provider:   consts.ModelProviderJina,
+		ModelName:  "jina-reranker-v2-base-multilingual",
+		BaseURL:    ts.URL + "/v1",
+		APIKey:     "jina-key",
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("GetReranker failed: %v", err)
+	}
+	if _, err := rk.Rerank(context.Background(), domain.RerankRequest{Query: "q", Documents: []string{"a"}}); err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+	if got := transport.n.Load(); got != 1 {
+		t.Fatalf("expected the request through the custom client, got %d", got)
+	}
+}
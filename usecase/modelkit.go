@@ -16,9 +16,21 @@ import (
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/model"
 
+	arkMultimodalEmb "github.com/chaitin/ModelKit/v2/components/embedder/ark"
 	bailianEmb "github.com/chaitin/ModelKit/v2/components/embedder/bailian"
+	geminiEmb "github.com/chaitin/ModelKit/v2/components/embedder/gemini"
+	jinaEmb "github.com/chaitin/ModelKit/v2/components/embedder/jina"
+	teiEmb "github.com/chaitin/ModelKit/v2/components/embedder/tei"
+	voyageEmb "github.com/chaitin/ModelKit/v2/components/embedder/voyage"
+	"github.com/chaitin/ModelKit/v2/components/model/reasoning"
+	"github.com/chaitin/ModelKit/v2/components/model/toolcall"
+	arkReranker "github.com/chaitin/ModelKit/v2/components/reranker/ark"
 	baaiReranker "github.com/chaitin/ModelKit/v2/components/reranker/baai"
 	bailianReranker "github.com/chaitin/ModelKit/v2/components/reranker/bailian"
+	cohereReranker "github.com/chaitin/ModelKit/v2/components/reranker/cohere"
+	jinaReranker "github.com/chaitin/ModelKit/v2/components/reranker/jina"
+	teiReranker "github.com/chaitin/ModelKit/v2/components/reranker/tei"
+	voyageReranker "github.com/chaitin/ModelKit/v2/components/reranker/voyage"
 	"github.com/chaitin/ModelKit/v2/consts"
 	"github.com/chaitin/ModelKit/v2/domain"
 	"github.com/chaitin/ModelKit/v2/utils"
@@ -54,16 +66,28 @@ func (m *ModelKit) ModelList(ctx context.Context, req *domain.ModelListReq) (*do
 	provider := consts.ParseModelProvider(req.Provider)
 
 	switch provider {
-	case consts.ModelProviderAzureOpenAI, consts.ModelProviderVolcengine:
-		return m.listStaticProvider(req, provider)
+	case consts.ModelProviderVolcengine:
+		return m.listVolcengine(ctx, req, httpClient)
+	case consts.ModelProviderAzureOpenAI:
+		return m.listAzureOpenAI(req, httpClient)
 	case consts.ModelProviderGemini:
-		return m.listGemini(ctx, req)
+		return m.listGemini(ctx, req, httpClient)
 	case consts.ModelProviderGithub:
 		return m.listGithub(req, httpClient)
+	case consts.ModelProviderOpenRouter:
+		return m.listOpenRouter(req, httpClient)
 	case consts.ModelProviderOllama:
 		return m.listOllama(req, httpClient)
 	case consts.ModelProviderGPUStack:
 		return m.listGPUStack(req, httpClient)
+	case consts.ModelProviderAnthropic:
+		return m.listAnthropic(req, httpClient)
+	case consts.ModelProviderAWSBedrock:
+		return m.listBedrock(req, httpClient)
+	case consts.ModelProviderVertexAI:
+		return m.listVertex(ctx, req, httpClient)
+	case consts.ModelProviderHuggingFaceTEI:
+		return m.listTEI(req, httpClient)
 	default:
 		return m.listOpenAI(req, httpClient, provider)
 	}
@@ -87,6 +111,12 @@ func (m *ModelKit) CheckModel(ctx context.Context, req *domain.CheckModelReq) (*
 }
 
 func (m *ModelKit) GetChatModel(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
+	v := m.ValidateChatParams(md)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	m.logParamWarnings(md, v.Warnings)
+
 	switch md.Provider {
 	case consts.ModelProviderDeepSeek:
 		return newDeepseekChatModel(ctx, md)
@@ -94,12 +124,49 @@ func (m *ModelKit) GetChatModel(ctx context.Context, md *domain.ModelMetadata) (
 		return newGeminiChatModel(ctx, md)
 	case consts.ModelProviderOllama:
 		return newOllamaChatModel(ctx, md)
+	case consts.ModelProviderAnthropic:
+		return newAnthropicChatModel(ctx, md)
+	case consts.ModelProviderAWSBedrock:
+		return newBedrockChatModel(ctx, md)
+	case consts.ModelProviderVertexAI:
+		return newVertexChatModel(ctx, md)
+	case consts.ModelProviderGPUStack:
+		return newGPUStackChatModel(ctx, md)
+	case consts.ModelProviderOpenAI, consts.ModelProviderAzureOpenAI:
+		if md.ChatAPI == consts.ChatAPIResponses {
+			return newResponsesChatModel(ctx, md)
+		}
+		return openai.NewChatModel(ctx, buildOpenAIChatConfig(md))
 	default:
 		cfg := buildOpenAIChatConfig(md)
 		return openai.NewChatModel(ctx, cfg)
 	}
 }
 
+// GetReasoningChatModel 在 GetChatModel 的基础上将 reasoning_content、<think> 标签与 Gemini thought
+// 统一到 schema.Message.ReasoningContent, Generate 与 Stream 均生效
+func (m *ModelKit) GetReasoningChatModel(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
+	cm, err := m.GetChatModel(ctx, md)
+	if err != nil {
+		return nil, err
+	}
+	return reasoning.NewChatModel(cm)
+}
+
+// GetToolCallingChatModel 返回支持 WithTools 的对话模型, 各提供商的工具调用结果统一补全 Index、ID 与参数,
+// 流式输出中的并行调用与参数增量可直接通过 schema.ConcatMessages 拼接
+func (m *ModelKit) GetToolCallingChatModel(ctx context.Context, md *domain.ModelMetadata) (model.ToolCallingChatModel, error) {
+	cm, err := m.GetChatModel(ctx, md)
+	if err != nil {
+		return nil, err
+	}
+	tcm, ok := cm.(model.ToolCallingChatModel)
+	if !ok {
+		return nil, fmt.Errorf("%s chat model does not support tool calling", md.Provider)
+	}
+	return toolcall.NewChatModel(tcm)
+}
+
 func (m *ModelKit) GetEmbedder(ctx context.Context, model *domain.ModelMetadata) (embedding.Embedder, error) {
 	// dimensions := consts.DefaultDimensions
 	cfg := &openaiEmb.EmbeddingConfig{
@@ -126,7 +193,7 @@ func (m *ModelKit) GetEmbedder(ctx context.Context, model *domain.ModelMetadata)
 		cfg.ByAzure = true
 		cfg.APIVersion = model.APIVersion
 		if cfg.APIVersion == "" {
-			cfg.APIVersion = "2024-10-21"
+			cfg.APIVersion = azureDefaultAPIVersion
 		}
 		return openaiEmb.NewEmbedder(ctx, cfg)
 	case consts.ModelProviderOllama:
@@ -142,13 +209,64 @@ func (m *ModelKit) GetEmbedder(ctx context.Context, model *domain.ModelMetadata)
 			Model:   model.ModelName,
 		})
 	case consts.ModelProviderVolcengine:
+		if arkMultimodalEmb.IsMultimodalModel(model.ModelName) {
+			return arkMultimodalEmb.NewEmbedder(ctx, &arkMultimodalEmb.EmbeddingConfig{
+				APIKey:    model.APIKey,
+				Model:     model.ModelName,
+				BaseURL:   model.BaseURL,
+				Dimension: model.EmbedderParam.Dimension,
+			})
+		}
 		return arkEmb.NewEmbedder(ctx, &arkEmb.EmbeddingConfig{
 			APIKey:  model.APIKey,
 			Model:   model.ModelName,
 			BaseURL: model.BaseURL,
 		})
+	case consts.ModelProviderAWSBedrock:
+		return newBedrockEmbedder(ctx, model)
+	case consts.ModelProviderGPUStack:
+		return newGPUStackEmbedder(ctx, model)
+	case consts.ModelProviderVertexAI:
+		return newVertexEmbedder(ctx, model)
+	case consts.ModelProviderJina:
+		return jinaEmb.NewEmbedder(ctx, &jinaEmb.EmbeddingConfig{
+			APIKey:       model.APIKey,
+			Model:        model.ModelName,
+			BaseURL:      model.BaseURL,
+			Dimension:    model.EmbedderParam.Dimension,
+			TextType:     model.EmbedderParam.TextType,
+			Task:         model.EmbedderParam.Task,
+			LateChunking: model.EmbedderParam.LateChunking,
+			MultiVector:  model.EmbedderParam.MultiVector,
+		})
+	case consts.ModelProviderHuggingFaceTEI:
+		return teiEmb.NewEmbedder(ctx, &teiEmb.EmbeddingConfig{
+			BaseURL:    model.BaseURL,
+			APIKey:     model.APIKey,
+			Dimension:  model.EmbedderParam.Dimension,
+			OutputType: model.EmbedderParam.OutputType,
+			HTTPClient: model.HTTPClient,
+		})
+	case consts.ModelProviderVoyageAI:
+		return voyageEmb.NewEmbedder(ctx, &voyageEmb.EmbeddingConfig{
+			APIKey:      model.APIKey,
+			Model:       model.ModelName,
+			BaseURL:     model.BaseURL,
+			Dimension:   model.EmbedderParam.Dimension,
+			TextType:    model.EmbedderParam.TextType,
+			OutputDtype: model.EmbedderParam.OutputDtype,
+		})
 	case consts.ModelProviderGemini:
-		return nil, fmt.Errorf("该提供商暂不支持向量模型")
+		return geminiEmb.NewEmbedder(ctx, &geminiEmb.EmbeddingConfig{
+			APIKey:      model.APIKey,
+			Model:       model.ModelName,
+			BaseURL:     geminiEmbedderBaseURL(model),
+			Dimension:   model.EmbedderParam.Dimension,
+			TextType:    model.EmbedderParam.TextType,
+			CountTokens: model.EmbedderParam.CountTokens,
+			// 与对话模型共用代理等 HTTP 设置
+			HTTPClient: model.HTTPClient,
+		})
 	default:
 		return openaiEmb.NewEmbedder(ctx, cfg)
 	}
@@ -158,6 +276,15 @@ func (m *ModelKit) GetReranker(ctx context.Context, model *domain.ModelMetadata)
 	if model.BaseURL == "https://dashscope.aliyuncs.com/api/v1/services/rerank/text-rerank/text-rerank#" {
 		model.Provider = consts.ModelProviderBaiLian
 	}
+	// 显式指定协议时优先于提供商
+	if model.RerankProtocol == consts.RerankProtocolCohere {
+		return cohereReranker.NewReranker(ctx, cohereReranker.RerankerConfig{
+			Model:      model.ModelName,
+			BaseUrl:    model.BaseURL,
+			APIKey:     model.APIKey,
+			HTTPClient: model.HTTPClient,
+		}), nil
+	}
 
 	switch model.Provider {
 	case consts.ModelProviderBaiLian:
@@ -166,6 +293,37 @@ func (m *ModelKit) GetReranker(ctx context.Context, model *domain.ModelMetadata)
 			BaseUrl: model.BaseURL,
 			APIKey:  model.APIKey,
 		}), nil
+	case consts.ModelProviderJina:
+		return jinaReranker.NewReranker(ctx, jinaReranker.RerankerConfig{
+			Model:      model.ModelName,
+			BaseUrl:    model.BaseURL,
+			APIKey:     model.APIKey,
+			HTTPClient: model.HTTPClient,
+		}), nil
+	case consts.ModelProviderVolcengine:
+		// 知识库服务不接受方舟推理的 API Key, 不复用 BaseURL 与 APIKey
+		return arkReranker.NewReranker(ctx, arkReranker.RerankerConfig{
+			Model:      model.ModelName,
+			BaseUrl:    model.VolcengineKnowledgeBaseURL,
+			APIKey:     model.VolcengineKnowledgeAPIKey,
+			HTTPClient: model.HTTPClient,
+		})
+	case consts.ModelProviderHuggingFaceTEI:
+		return teiReranker.NewReranker(ctx, teiReranker.RerankerConfig{
+			Model:      model.ModelName,
+			BaseUrl:    model.BaseURL,
+			APIKey:     model.APIKey,
+			HTTPClient: model.HTTPClient,
+		}), nil
+	case consts.ModelProviderGPUStack:
+		return newGPUStackReranker(ctx, model), nil
+	case consts.ModelProviderVoyageAI:
+		return voyageReranker.NewReranker(ctx, voyageReranker.RerankerConfig{
+			Model:      model.ModelName,
+			BaseUrl:    model.BaseURL,
+			APIKey:     model.APIKey,
+			HTTPClient: model.HTTPClient,
+		}), nil
 	default:
 		return baaiReranker.NewReranker(ctx, baaiReranker.RerankerConfig{
 			Model:   model.ModelName,
@@ -200,3 +358,12 @@ func (m *ModelKit) UseEmbedder(ctx context.Context, e embedding.Embedder, texts
 	}
 	return out, nil
 }
+
+// UseMultimodalEmbedder 使用图文混合输入生成向量, 每条输入对应一个向量
+func (m *ModelKit) UseMultimodalEmbedder(ctx context.Context, e embedding.Embedder, inputs []domain.EmbeddingInput) (*domain.EmbeddingsResponse, error) {
+	me, ok := e.(domain.MultimodalEmbedder)
+	if !ok {
+		return nil, fmt.Errorf("this model not support image input")
+	}
+	return me.EmbedInputs(ctx, inputs)
+}
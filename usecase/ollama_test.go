@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+func TestModelList_OllamaCapabilities(t *testing.T) {
+	shows := map[string]string{
+		"qwen3:8b":         `{"details":{"family":"qwen3"},"model_info":{"general.architecture":"qwen3","qwen3.context_length":40960,"qwen3.embedding_length":4096},"capabilities":["completion","tools","thinking"]}`,
+		"my-vl:latest":     `{"details":{"family":"mllama"},"model_info":{"general.architecture":"mllama","mllama.context_length":131072},"capabilities":["completion","vision"]}`,
+		"granite-s:latest": `{"details":{"family":"bert"},"model_info":{"general.architecture":"bert","bert.context_length":512,"bert.embedding_length":384},"capabilities":["embedding"]}`,
+	}
+	ts := standin.New(t, standin.Routes{
+		"GET /api/tags": standin.JSON(`{"models":[
+			{"name":"qwen3:8b","model":"qwen3:8b","size":5225388164,"details":{"format":"gguf","family":"qwen3","parameter_size":"8.2B","quantization_level":"Q4_K_M"}},
+			{"name":"my-vl:latest","model":"my-vl:latest","size":7816589186,"details":{"parameter_size":"10.7B","quantization_level":"Q4_K_M"}},
+			{"name":"granite-s:latest","model":"granite-s:latest","size":62528010,"details":{"family":"bert","parameter_size":"33M","quantization_level":"F16"}}]}`),
+		"POST /api/show": func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Model string `json:"model"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			show, ok := shows[body.Model]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			standin.JSON(show)(w, r)
+		},
+	})
+
+	mk := NewModelKit(nil)
+	list := func(typ string) []domain.ModelListItem {
+		resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+			Provider: string(consts.ModelProviderOllama),
+			BaseURL:  ts.URL,
+			Type:     typ,
+		})
+		if err != nil || resp.Error != "" {
+			t.Fatalf("ModelList failed: %v %+v", err, resp)
+		}
+		return resp.Models
+	}
+
+	chat := list("llm")
+	if len(chat) != 2 || chat[0].Model != "qwen3:8b" || chat[1].Model != "my-vl:latest" {
+		t.Fatalf("unexpected chat models: %+v", chat)
+	}
+	if chat[0].ContextLength != 40960 || chat[0].ParameterSize != "8.2B" || chat[0].QuantizationLevel != "Q4_K_M" || chat[0].Size != 5225388164 {
+		t.Fatalf("unexpected metadata: %+v", chat[0])
+	}
+	if chat[1].Family != "mllama" || chat[1].ContextLength != 131072 {
+		t.Fatalf("unexpected metadata: %+v", chat[1])
+	}
+
+	vl := list("analysis-vl")
+	if len(vl) != 1 || vl[0].Model != "my-vl:latest" {
+		t.Fatalf("unexpected vision models: %+v", vl)
+	}
+
+	emb := list("embedding")
+	if len(emb) != 1 || emb[0].Model != "granite-s:latest" || emb[0].EmbeddingLength != 384 {
+		t.Fatalf("unexpected embedding models: %+v", emb)
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+func TestModelList_OpenRouter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/models" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[
+			{"id":"openai/gpt-4o","context_length":128000,"architecture":{"modality":"text+image->text","input_modalities":["text","image","file"],"output_modalities":["text"]},
+			 "pricing":{"prompt":"0.0000025","completion":"0.00001","image":"0.003613"},"top_provider":{"context_length":128000,"max_completion_tokens":16384},
+			 "supported_parameters":["tools","tool_choice","max_tokens","temperature","response_format"]},
+			{"id":"deepseek/deepseek-r1","context_length":163840,"architecture":{"modality":"text->text","input_modalities":["text"],"output_modalities":["text"]},
+			 "pricing":{"prompt":"0.0000004","completion":"0.000002"},"top_provider":{"context_length":163840},
+			 "supported_parameters":["reasoning","include_reasoning","max_tokens"]}]}`))
+	}))
+	defer ts.Close()
+
+	mk := NewModelKit(nil)
+	list := func(typ string) []domain.ModelListItem {
+		resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+			Provider: string(consts.ModelProviderOpenRouter),
+			BaseURL:  ts.URL + "/api/v1",
+			APIKey:   "sk-or-test",
+			Type:     typ,
+		})
+		if err != nil || resp.Error != "" {
+			t.Fatalf("ModelList failed: %v %+v", err, resp)
+		}
+		return resp.Models
+	}
+
+	chat := list("llm")
+	if len(chat) != 2 {
+		t.Fatalf("unexpected chat models: %+v", chat)
+	}
+	gpt := chat[0]
+	if gpt.ContextLength != 128000 || gpt.MaxOutputTokens != 16384 || gpt.Pricing == nil || gpt.Pricing.Prompt != "0.0000025" || len(gpt.SupportedParameters) != 5 {
+		t.Fatalf("unexpected metadata: %+v", gpt)
+	}
+
+	for typ, want := range map[string]string{"analysis-vl": "openai/gpt-4o", "function_call": "openai/gpt-4o"} {
+		got := list(typ)
+		if len(got) != 1 || got[0].Model != want {
+			t.Fatalf("unexpected %s models: %+v", typ, got)
+		}
+	}
+	if got := list("embedding"); len(got) != 0 {
+		t.Fatalf("unexpected embedding models: %+v", got)
+	}
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+// 对话模型参数名, 与 ModelMetadata 的 json 字段一致
+const (
+	paramMaxTokens        = "max_tokens"
+	paramTemperature      = "temperature"
+	paramTopP             = "top_p"
+	paramStop             = "stop"
+	paramPresencePenalty  = "presence_penalty"
+	paramFrequencyPenalty = "frequency_penalty"
+	paramResponseFormat   = "response_format"
+	paramSeed             = "seed"
+	paramLogitBias        = "logit_bias"
+	paramAPIHeader        = "api_header"
+	paramReasoningEffort  = "reasoning_effort"
+	paramEnableThinking   = "enable_thinking"
+	paramThinkingBudget   = "thinking_budget"
+	paramChatAPI          = "chat_api"
+	paramResponsesParam   = "responses_param"
+	paramGeminiParam      = "gemini_param"
+)
+
+// 对话模型使用的客户端, 同一提供商可能使用不同的客户端, 见 GetChatModel
+const (
+	chatClientOpenAI    = "openai"
+	chatClientResponses = "responses"
+	chatClientDeepSeek  = "deepseek"
+	chatClientGemini    = "gemini"
+	chatClientVertex    = "vertex"
+	chatClientOllama    = "ollama"
+	chatClientAnthropic = "anthropic"
+	chatClientBedrock   = "bedrock"
+)
+
+// unsupportedChatParams 各客户端构建时会忽略的参数
+var unsupportedChatParams = map[string][]string{
+	chatClientOpenAI:    {paramResponsesParam, paramGeminiParam},
+	chatClientResponses: {paramStop, paramPresencePenalty, paramFrequencyPenalty, paramSeed, paramLogitBias, paramEnableThinking, paramThinkingBudget, paramGeminiParam},
+	chatClientDeepSeek:  {paramSeed, paramLogitBias, paramReasoningEffort, paramThinkingBudget, paramChatAPI, paramResponsesParam, paramGeminiParam},
+	chatClientGemini:    {paramLogitBias, paramChatAPI, paramResponsesParam},
+	chatClientVertex:    {paramLogitBias, paramAPIHeader, paramChatAPI, paramResponsesParam},
+	chatClientOllama:    {paramMaxTokens, paramLogitBias, paramThinkingBudget, paramChatAPI, paramResponsesParam, paramGeminiParam},
+	chatClientAnthropic: {paramPresencePenalty, paramFrequencyPenalty, paramSeed, paramLogitBias, paramReasoningEffort, paramChatAPI, paramResponsesParam, paramGeminiParam},
+	chatClientBedrock:   {paramPresencePenalty, paramFrequencyPenalty, paramSeed, paramLogitBias, paramAPIHeader, paramReasoningEffort, paramEnableThinking, paramThinkingBudget, paramChatAPI, paramResponsesParam, paramGeminiParam},
+}
+
+var reasoningEfforts = []string{"minimal", "low", "medium", "high"}
+
+// reasoningEffortProviders OpenAI 兼容接口中接受 reasoning_effort 的提供商, 其余提供商不发送.
+// Other 为用户自行配置的兼容接口, 按 OpenAI 处理
+var reasoningEffortProviders = []consts.ModelProvider{
+	consts.ModelProviderOpenAI,
+	consts.ModelProviderAzureOpenAI,
+	consts.ModelProviderOllama,
+	consts.ModelProviderOpenRouter,
+	consts.ModelProviderGroq,
+	consts.ModelProviderGrok,
+	consts.ModelProviderGithub,
+	consts.ModelProviderOther,
+}
+
+// chatClient 与 GetChatModel 的分支保持一致
+func chatClient(md *domain.ModelMetadata) string {
+	switch md.Provider {
+	case consts.ModelProviderDeepSeek:
+		return chatClientDeepSeek
+	case consts.ModelProviderGemini:
+		return chatClientGemini
+	case consts.ModelProviderVertexAI:
+		return chatClientVertex
+	case consts.ModelProviderOllama:
+		if strings.HasSuffix(md.BaseURL, "/v1") {
+			return chatClientOpenAI
+		}
+		return chatClientOllama
+	case consts.ModelProviderAnthropic:
+		return chatClientAnthropic
+	case consts.ModelProviderAWSBedrock:
+		return chatClientBedrock
+	case consts.ModelProviderOpenAI, consts.ModelProviderAzureOpenAI:
+		if md.ChatAPI == consts.ChatAPIResponses {
+			return chatClientResponses
+		}
+	}
+	return chatClientOpenAI
+}
+
+// chatParamSet 返回已设置的参数
+func chatParamSet(md *domain.ModelMetadata) []string {
+	p := md.ResponsesParam
+	g := md.GeminiParam
+	set := map[string]bool{
+		paramMaxTokens:        md.MaxTokens != nil,
+		paramTemperature:      md.Temperature != nil,
+		paramTopP:             md.TopP != nil,
+		paramStop:             len(md.Stop) > 0,
+		paramPresencePenalty:  md.PresencePenalty != nil,
+		paramFrequencyPenalty: md.FrequencyPenalty != nil,
+		paramResponseFormat:   md.ResponseFormat != nil,
+		paramSeed:             md.Seed != nil,
+		paramLogitBias:        len(md.LogitBias) > 0,
+		paramAPIHeader:        md.APIHeader != "",
+		paramReasoningEffort:  md.ReasoningEffort != "",
+		paramEnableThinking:   md.EnableThinking != nil,
+		paramThinkingBudget:   md.ThinkingBudget != nil,
+		paramChatAPI:          md.ChatAPI != consts.ChatAPIChatCompletions,
+		paramResponsesParam: p.ReasoningEffort != "" || p.ReasoningSummary != "" || len(p.BuiltinTools) > 0 ||
+			p.PreviousResponseID != "" || p.Store != nil,
+		paramGeminiParam: g.ThinkingBudget != nil || g.ThinkingLevel != "" || g.IncludeThoughts != nil ||
+			len(g.SafetySettings) > 0 || g.ResponseMIMEType != "" || len(g.ResponseSchema) > 0,
+	}
+	params := make([]string, 0, len(set))
+	for k, v := range set {
+		if v {
+			params = append(params, k)
+		}
+	}
+	slices.Sort(params)
+	return params
+}
+
+// ValidateChatParams 在构建客户端前校验参数取值, 并列出当前提供商会忽略的参数
+func (m *ModelKit) ValidateChatParams(md *domain.ModelMetadata) *domain.ParamValidation {
+	client := chatClient(md)
+	v := &domain.ParamValidation{}
+	v.Errors = chatParamErrors(md, client)
+	for _, param := range chatParamSet(md) {
+		if msg := ignoredChatParam(md, client, param); msg != "" {
+			v.Warnings = append(v.Warnings, domain.ParamWarning{Param: param, Message: msg})
+		}
+	}
+	return v
+}
+
+func chatParamErrors(md *domain.ModelMetadata, client string) []domain.ParamError {
+	var errs []domain.ParamError
+	checkRange := func(param string, value *float32, lo, hi float32) {
+		if value != nil && (*value < lo || *value > hi) {
+			errs = append(errs, domain.ParamError{Param: param, Value: *value, Message: fmt.Sprintf("must be between %g and %g", lo, hi)})
+		}
+	}
+	if md.MaxTokens != nil && *md.MaxTokens <= 0 {
+		errs = append(errs, domain.ParamError{Param: paramMaxTokens, Value: *md.MaxTokens, Message: "must be greater than 0"})
+	}
+	// Anthropic Messages API 的 temperature 范围为 0-1
+	maxTemperature := float32(2)
+	if client == chatClientAnthropic {
+		maxTemperature = 1
+	}
+	checkRange(paramTemperature, md.Temperature, 0, maxTemperature)
+	checkRange(paramTopP, md.TopP, 0, 1)
+	checkRange(paramPresencePenalty, md.PresencePenalty, -2, 2)
+	checkRange(paramFrequencyPenalty, md.FrequencyPenalty, -2, 2)
+	tokens := make([]string, 0, len(md.LogitBias))
+	for token := range md.LogitBias {
+		tokens = append(tokens, token)
+	}
+	slices.Sort(tokens)
+	for _, token := range tokens {
+		if bias := md.LogitBias[token]; bias < -100 || bias > 100 {
+			errs = append(errs, domain.ParamError{Param: paramLogitBias + "." + token, Value: bias, Message: "must be between -100 and 100"})
+		}
+	}
+	if md.ReasoningEffort != "" && !slices.Contains(reasoningEfforts, md.ReasoningEffort) {
+		errs = append(errs, domain.ParamError{Param: paramReasoningEffort, Value: md.ReasoningEffort, Message: "must be one of " + strings.Join(reasoningEfforts, ", ")})
+	}
+	if md.ThinkingBudget != nil {
+		// Gemini 使用 -1 表示由模型动态决定
+		minBudget := 0
+		if client == chatClientGemini || client == chatClientVertex {
+			minBudget = -1
+		}
+		if *md.ThinkingBudget < minBudget {
+			errs = append(errs, domain.ParamError{Param: paramThinkingBudget, Value: *md.ThinkingBudget, Message: fmt.Sprintf("must be greater than or equal to %d", minBudget)})
+		}
+	}
+	// Anthropic 的思考预算至少 1024, 且 max_tokens 必须大于预算
+	if client == chatClientAnthropic {
+		if budget := anthropicThinkingBudget(md); budget != nil {
+			if *budget < anthropicMinThinkingBudget {
+				errs = append(errs, domain.ParamError{Param: paramThinkingBudget, Value: *budget, Message: fmt.Sprintf("must be at least %d when extended thinking is enabled", anthropicMinThinkingBudget)})
+			} else if md.MaxTokens != nil && *md.MaxTokens > 0 && *md.MaxTokens <= *budget {
+				errs = append(errs, domain.ParamError{Param: paramMaxTokens, Value: *md.MaxTokens, Message: fmt.Sprintf("must be greater than thinking_budget %d", *budget)})
+			}
+		}
+	}
+	return errs
+}
+
+// ignoredChatParam 参数会被忽略时返回原因
+func ignoredChatParam(md *domain.ModelMetadata, client, param string) string {
+	if slices.Contains(unsupportedChatParams[client], param) {
+		return fmt.Sprintf("not supported by %s, ignored", md.Provider)
+	}
+	qwenThinking := md.Provider == consts.ModelProviderBaiLian || md.Provider == consts.ModelProviderSiliconFlow
+	switch param {
+	case paramTemperature:
+		if (client == chatClientOpenAI || client == chatClientResponses) && shouldIgnoreOpenAITemperature(md.ModelName) {
+			return fmt.Sprintf("not supported by reasoning model %s, ignored", md.ModelName)
+		}
+	case paramReasoningEffort:
+		if client == chatClientOpenAI && qwenThinking {
+			return fmt.Sprintf("not supported by %s, use enable_thinking instead", md.Provider)
+		}
+		if client == chatClientOpenAI && !slices.Contains(reasoningEffortProviders, md.Provider) {
+			return fmt.Sprintf("not supported by %s, use extra_body instead", md.Provider)
+		}
+		if (client == chatClientGemini || client == chatClientVertex) && !strings.HasPrefix(md.ModelName, "gemini-3") {
+			return "only supported by gemini 3 models, use thinking_budget instead"
+		}
+	case paramResponseFormat:
+		if client == chatClientAnthropic && anthropicThinkingBudget(md) != nil {
+			return "not enforced while extended thinking is enabled"
+		}
+	case paramEnableThinking, paramThinkingBudget:
+		if client == chatClientOpenAI && !qwenThinking {
+			return fmt.Sprintf("not supported by %s, use reasoning_effort or extra_body instead", md.Provider)
+		}
+	}
+	return ""
+}
+
+func (m *ModelKit) logParamWarnings(md *domain.ModelMetadata, warnings []domain.ParamWarning) {
+	if len(warnings) == 0 {
+		return
+	}
+	msgs := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		msgs = append(msgs, w.String())
+	}
+	if m.logger != nil {
+		m.logger.Warn("chat model params ignored", slog.String("provider", string(md.Provider)), slog.String("model", md.ModelName), slog.Any("warnings", msgs))
+	} else {
+		log.Printf("chat model params ignored, provider=%s, model=%s: %s", md.Provider, md.ModelName, strings.Join(msgs, "; "))
+	}
+}
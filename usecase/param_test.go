@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/libs/acl/openai"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+func TestValidateChatParams_Errors(t *testing.T) {
+	f := func(v float32) *float32 { return &v }
+	i := func(v int) *int { return &v }
+	testCases := []struct {
+		name   string
+		md     *domain.ModelMetadata
+		params []string
+	}{
+		{
+			name:   "valid",
+			md:     &domain.ModelMetadata{Provider: consts.ModelProviderOpenAI, Temperature: f(2), TopP: f(0), MaxTokens: i(1)},
+			params: nil,
+		},
+		{
+			name: "openai ranges",
+			md: &domain.ModelMetadata{
+				Provider: consts.ModelProviderOpenAI, Temperature: f(2.5), TopP: f(1.2), MaxTokens: i(0),
+				PresencePenalty: f(-3), LogitBias: map[string]int{"50256": -101, "42": 10}, ReasoningEffort: "max",
+			},
+			params: []string{paramMaxTokens, paramTemperature, paramTopP, paramPresencePenalty, paramLogitBias + ".50256", paramReasoningEffort},
+		},
+		{
+			name:   "anthropic temperature",
+			md:     &domain.ModelMetadata{Provider: consts.ModelProviderAnthropic, Temperature: f(1.5), ThinkingBudget: i(-1)},
+			params: []string{paramTemperature, paramThinkingBudget},
+		},
+		{
+			name:   "anthropic budget below minimum",
+			md:     &domain.ModelMetadata{Provider: consts.ModelProviderAnthropic, ThinkingBudget: i(512)},
+			params: []string{paramThinkingBudget},
+		},
+		{
+			name:   "anthropic max_tokens not above budget",
+			md:     &domain.ModelMetadata{Provider: consts.ModelProviderAnthropic, ThinkingBudget: i(8192), MaxTokens: i(4096)},
+			params: []string{paramMaxTokens},
+		},
+		{
+			name:   "anthropic budget without max_tokens",
+			md:     &domain.ModelMetadata{Provider: consts.ModelProviderAnthropic, ThinkingBudget: i(8192)},
+			params: nil,
+		},
+		{
+			name:   "gemini dynamic thinking",
+			md:     &domain.ModelMetadata{Provider: consts.ModelProviderGemini, ThinkingBudget: i(-1)},
+			params: nil,
+		},
+	}
+	mk := NewModelKit(nil)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := mk.ValidateChatParams(tc.md)
+			if len(v.Errors) != len(tc.params) {
+				t.Fatalf("unexpected errors: %+v", v.Errors)
+			}
+			for i, p := range tc.params {
+				if v.Errors[i].Param != p {
+					t.Fatalf("unexpected errors: %+v", v.Errors)
+				}
+			}
+		})
+	}
+
+	_, err := mk.GetChatModel(context.Background(), testCases[1].md)
+	var pe *domain.ParamValidationError
+	if !errors.As(err, &pe) || len(pe.Errors) != len(testCases[1].params) {
+		t.Fatalf("expected ParamValidationError, got %v", err)
+	}
+}
+
+func TestValidateChatParams_Warnings(t *testing.T) {
+	f := func(v float32) *float32 { return &v }
+	i := func(v int) *int { return &v }
+	on := true
+	testCases := []struct {
+		name     string
+		md       *domain.ModelMetadata
+		warnings []string
+	}{
+		{
+			name: "deepseek",
+			md: &domain.ModelMetadata{
+				Provider: consts.ModelProviderDeepSeek, ModelName: "deepseek-chat", Temperature: f(1), Seed: i(1),
+				LogitBias:      map[string]int{"1": 1},
+				ResponseFormat: &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject},
+			},
+			warnings: []string{paramLogitBias, paramSeed},
+		},
+		{
+			name: "anthropic structured output with thinking",
+			md: &domain.ModelMetadata{
+				Provider: consts.ModelProviderAnthropic, ModelName: "claude-sonnet-4-5", EnableThinking: &on,
+				ResponseFormat: &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject},
+			},
+			warnings: []string{paramResponseFormat},
+		},
+		{
+			name:     "ollama native",
+			md:       &domain.ModelMetadata{Provider: consts.ModelProviderOllama, BaseURL: "http://localhost:11434", MaxTokens: i(100), EnableThinking: &on},
+			warnings: []string{paramMaxTokens},
+		},
+		{
+			name:     "ollama openai compatible",
+			md:       &domain.ModelMetadata{Provider: consts.ModelProviderOllama, BaseURL: "http://localhost:11434/v1", MaxTokens: i(100)},
+			warnings: nil,
+		},
+		{
+			name:     "openai reasoning model",
+			md:       &domain.ModelMetadata{Provider: consts.ModelProviderOpenAI, ModelName: "o3-mini", Temperature: f(1), ThinkingBudget: i(100), ReasoningEffort: "low"},
+			warnings: []string{paramTemperature, paramThinkingBudget},
+		},
+		{
+			name:     "bailian thinking",
+			md:       &domain.ModelMetadata{Provider: consts.ModelProviderBaiLian, ModelName: "qwen3-32b", EnableThinking: &on, ReasoningEffort: "low"},
+			warnings: []string{paramReasoningEffort},
+		},
+		{
+			name:     "reasoning_effort not accepted",
+			md:       &domain.ModelMetadata{Provider: consts.ModelProviderZhiPu, ModelName: "glm-4.5", ReasoningEffort: "low"},
+			warnings: []string{paramReasoningEffort},
+		},
+		{
+			name:     "reasoning_effort on openrouter",
+			md:       &domain.ModelMetadata{Provider: consts.ModelProviderOpenRouter, ModelName: "openai/o4-mini", ReasoningEffort: "low"},
+			warnings: nil,
+		},
+		{
+			name:     "vertex",
+			md:       &domain.ModelMetadata{Provider: consts.ModelProviderVertexAI, ModelName: "gemini-2.5-flash", APIHeader: "X-Test=1", ReasoningEffort: "low"},
+			warnings: []string{paramAPIHeader, paramReasoningEffort},
+		},
+	}
+	mk := NewModelKit(nil)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := mk.ValidateChatParams(tc.md)
+			if len(v.Errors) != 0 || len(v.Warnings) != len(tc.warnings) {
+				t.Fatalf("unexpected result: %+v", v)
+			}
+			for i, p := range tc.warnings {
+				if v.Warnings[i].Param != p {
+					t.Fatalf("unexpected warnings: %+v", v.Warnings)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckModel_ParamWarnings(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer ts.Close()
+
+	temperature := float32(0.5)
+	resp, err := NewModelKit(nil).CheckModel(context.Background(), &domain.CheckModelReq{
+		Provider: string(consts.ModelProviderOpenAI),
+		Model:    "o3-mini",
+		BaseURL:  ts.URL + "/v1",
+		APIKey:   "sk-test",
+		Type:     string(consts.ModelTypeChat),
+		Param:    &domain.ModelParam{Temperature: &temperature, R1Enabled: true},
+	})
+	if err != nil || resp.Error != "" || resp.Content != "ok" {
+		t.Fatalf("CheckModel failed: %v %+v", err, resp)
+	}
+	if len(resp.Warnings) != 2 || resp.Warnings[0].Param != paramEnableThinking || resp.Warnings[1].Param != paramTemperature {
+		t.Fatalf("unexpected warnings: %+v", resp.Warnings)
+	}
+}
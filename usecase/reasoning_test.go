@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+func collectStream(t *testing.T, sr *schema.StreamReader[*schema.Message]) *schema.Message {
+	t.Helper()
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	msg, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		t.Fatalf("ConcatMessages failed: %v", err)
+	}
+	return msg
+}
+
+// 标签拆分的细节见 components/model/reasoning, 这里只校验各提供商都经过推理包装
+func TestGetReasoningChatModel_ThinkTags(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"/v1/chat/completions": func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Stream bool `json:"stream"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if !body.Stream {
+				standin.JSON(`{"id":"1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"<think>\nUser greets me.\n</think>\n\nHello!"},"finish_reason":"stop"}]}`)(w, r)
+				return
+			}
+			standin.WriteSSE(w,
+				`{"id":"1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"role":"assistant","content":"<thought>plan</tho"}}]}`,
+				`{"id":"1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"ught>\nHello!"}}]}`,
+				`[DONE]`)
+		},
+	})
+
+	ctx := context.Background()
+	for _, md := range []*domain.ModelMetadata{
+		{Provider: consts.ModelProviderSiliconFlow, ModelName: "deepseek-ai/DeepSeek-R1"},
+		{Provider: consts.ModelProviderOther, ModelName: "gemini-2.5-flash"},
+	} {
+		md.BaseURL = ts.URL + "/v1"
+		md.APIKey = "sk-test"
+		cm, err := NewModelKit(nil).GetReasoningChatModel(ctx, md)
+		if err != nil {
+			t.Fatalf("GetReasoningChatModel failed: %v", err)
+		}
+		msg, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		if msg.ReasoningContent != "User greets me." || msg.Content != "Hello!" {
+			t.Fatalf("%s: unexpected message: %q %q", md.Provider, msg.ReasoningContent, msg.Content)
+		}
+		sr, err := cm.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+		if msg := collectStream(t, sr); msg.ReasoningContent != "plan" || msg.Content != "Hello!" {
+			t.Fatalf("%s: unexpected stream message: %q %q", md.Provider, msg.ReasoningContent, msg.Content)
+		}
+	}
+}
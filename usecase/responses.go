@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components/model"
+
+	"github.com/chaitin/ModelKit/v2/components/model/responses"
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+// newResponsesChatModel 通过 OpenAI Responses API 对话, 适用于 o 系列与 gpt-5 等推理模型
+func newResponsesChatModel(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
+	p := md.ResponsesParam
+	cfg := &responses.ChatModelConfig{
+		APIKey:             md.APIKey,
+		Model:              md.ModelName,
+		BaseURL:            md.BaseURL,
+		MaxOutputTokens:    md.MaxTokens,
+		TopP:               md.TopP,
+		ReasoningEffort:    p.ReasoningEffort,
+		ReasoningSummary:   p.ReasoningSummary,
+		BuiltinTools:       p.BuiltinTools,
+		PreviousResponseID: p.PreviousResponseID,
+		Store:              p.Store,
+		TextFormat:         responsesTextFormat(md),
+	}
+	if cfg.ReasoningEffort == "" {
+		cfg.ReasoningEffort = md.ReasoningEffort
+	}
+	if !shouldIgnoreOpenAITemperature(md.ModelName) {
+		cfg.Temperature = md.Temperature
+	}
+	if md.Provider == consts.ModelProviderAzureOpenAI {
+		cfg.ByAzure = true
+		cfg.APIVersion = md.APIVersion
+	}
+	cfg.HTTPClient = chatHTTPClient(md)
+	return responses.NewChatModel(ctx, cfg)
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/components/model/responses"
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+func TestGetChatModel_OpenAIResponses(t *testing.T) {
+	// 流式事件与输出项的解析见 components/model/responses, 这里校验 ResponsesParam 的映射
+	ts := standin.New(t, standin.Routes{
+		"POST /v1/responses": func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if _, ok := body["temperature"]; ok {
+				t.Errorf("temperature should be dropped for reasoning models: %v", body)
+			}
+			reasoning, _ := body["reasoning"].(map[string]any)
+			tools, _ := body["tools"].([]any)
+			input, _ := body["input"].([]any)
+			if body["instructions"] != "be brief" || reasoning["effort"] != "high" || reasoning["summary"] != "auto" ||
+				body["previous_response_id"] != "resp_prev" || len(tools) != 1 || len(input) != 1 {
+				t.Errorf("unexpected request body: %v", body)
+			}
+			standin.JSON(`{
+				"id": "resp_123", "status": "completed",
+				"output": [
+					{"type": "reasoning", "summary": [{"type": "summary_text", "text": "thinking"}]},
+					{"type": "web_search_call", "status": "completed"},
+					{"type": "message", "role": "assistant", "content": [{"type": "output_text", "text": "Hello"}]}
+				],
+				"usage": {"input_tokens": 10, "input_tokens_details": {"cached_tokens": 4}, "output_tokens": 5, "total_tokens": 15}
+			}`)(w, r)
+		},
+	}, standin.Header("Authorization", "Bearer sk-test"))
+
+	temperature := float32(0.5)
+	mk := NewModelKit(nil)
+	cm, err := mk.GetChatModel(context.Background(), &domain.ModelMetadata{
+		Provider:    consts.ModelProviderOpenAI,
+		ModelName:   "o3",
+		BaseURL:     ts.URL + "/v1",
+		APIKey:      "sk-test",
+		Temperature: &temperature,
+		ChatAPI:     consts.ChatAPIResponses,
+		ResponsesParam: domain.ResponsesParam{
+			ReasoningEffort:    "high",
+			ReasoningSummary:   "auto",
+			BuiltinTools:       []map[string]any{{"type": "web_search"}},
+			PreviousResponseID: "resp_prev",
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	msg, err := cm.Generate(context.Background(), []*schema.Message{
+		schema.SystemMessage("be brief"),
+		schema.UserMessage("hi"),
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if msg.Content != "Hello" || msg.ReasoningContent != "thinking" || msg.Extra[responses.ExtraKeyResponseID] != "resp_123" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if u := msg.ResponseMeta.Usage; u.PromptTokens != 10 || u.PromptTokenDetails.CachedTokens != 4 || u.TotalTokens != 15 ||
+		msg.ResponseMeta.FinishReason != "stop" {
+		t.Fatalf("unexpected response meta: %+v", msg.ResponseMeta)
+	}
+}
+
+func TestGetChatModel_AzureResponses(t *testing.T) {
+	cases := map[string]string{
+		"":                   "/openai/v1/responses",
+		"2025-04-01-preview": "/openai/responses?api-version=2025-04-01-preview",
+	}
+	for version, want := range cases {
+		ts := standin.New(t, standin.Routes{
+			"POST " + strings.Split(want, "?")[0]: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.RequestURI() != want {
+					t.Errorf("unexpected request: %s", r.URL.RequestURI())
+				}
+				standin.JSON(`{"id":"resp_1","status":"completed","output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":"ok"}]}]}`)(w, r)
+			},
+		}, standin.Header("api-key", "azure-key"))
+
+		mk := NewModelKit(nil)
+		resp, err := mk.CheckModel(context.Background(), &domain.CheckModelReq{
+			Provider:   string(consts.ModelProviderAzureOpenAI),
+			Model:      "my-gpt5-deployment",
+			BaseURL:    ts.URL + "/openai",
+			APIKey:     "azure-key",
+			APIVersion: version,
+			Type:       string(consts.ModelTypeChat),
+			ChatAPI:    string(consts.ChatAPIResponses),
+		})
+		if err != nil || resp.Error != "" || resp.Content != "ok" {
+			t.Fatalf("CheckModel failed for %q: %v %+v", version, err, resp)
+		}
+	}
+}
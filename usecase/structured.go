@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/cloudwego/eino-ext/libs/acl/openai"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/components/model/responses"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+// responseSchema 将 OpenAI 风格的 response_format 转换为 JSON Schema,
+// json_object 视为任意对象, 未要求结构化输出时返回 nil
+func responseSchema(md *domain.ModelMetadata) any {
+	rf := md.ResponseFormat
+	if rf == nil {
+		return nil
+	}
+	switch rf.Type {
+	case openai.ChatCompletionResponseFormatTypeJSONSchema:
+		if rf.JSONSchema != nil && rf.JSONSchema.JSONSchema != nil {
+			return rf.JSONSchema.JSONSchema
+		}
+		return map[string]any{"type": "object"}
+	case openai.ChatCompletionResponseFormatTypeJSONObject:
+		return map[string]any{"type": "object"}
+	}
+	return nil
+}
+
+// ollamaFormat 原生接口的 format 支持 "json" 或完整的 JSON Schema
+func ollamaFormat(md *domain.ModelMetadata) (json.RawMessage, error) {
+	rf := md.ResponseFormat
+	if rf == nil {
+		return nil, nil
+	}
+	switch rf.Type {
+	case openai.ChatCompletionResponseFormatTypeJSONSchema:
+		return json.Marshal(responseSchema(md))
+	case openai.ChatCompletionResponseFormatTypeJSONObject:
+		return json.RawMessage(`"json"`), nil
+	}
+	return nil, nil
+}
+
+// responsesTextFormat Responses API 的 text.format 与 response_format 字段一致, 只是不再嵌套 json_schema
+func responsesTextFormat(md *domain.ModelMetadata) *responses.TextFormat {
+	rf := md.ResponseFormat
+	if rf == nil {
+		return nil
+	}
+	switch rf.Type {
+	case openai.ChatCompletionResponseFormatTypeJSONSchema:
+		f := &responses.TextFormat{Type: string(rf.Type), Name: "response", Schema: responseSchema(md)}
+		if js := rf.JSONSchema; js != nil {
+			if js.Name != "" {
+				f.Name = js.Name
+			}
+			f.Description = js.Description
+			f.Strict = js.Strict
+		}
+		return f
+	case openai.ChatCompletionResponseFormatTypeJSONObject:
+		return &responses.TextFormat{Type: string(rf.Type)}
+	}
+	return nil
+}
+
+// jsonPrompt 只支持 json_object 的提供商通过提示词约束输出结构,
+// DeepSeek 同时要求提示词中包含 json 字样
+func jsonPrompt(md *domain.ModelMetadata) (string, error) {
+	rf := md.ResponseFormat
+	if rf == nil {
+		return "", nil
+	}
+	switch rf.Type {
+	case openai.ChatCompletionResponseFormatTypeJSONSchema:
+		raw, err := json.Marshal(responseSchema(md))
+		if err != nil {
+			return "", err
+		}
+		return "Respond only with a JSON object that conforms to the following JSON Schema, without any other text:\n" + string(raw), nil
+	case openai.ChatCompletionResponseFormatTypeJSONObject:
+		return "Respond only with a valid JSON object, without any other text.", nil
+	}
+	return "", nil
+}
+
+// jsonPromptChatModel 在输入消息前追加要求输出 JSON 的 system 消息
+type jsonPromptChatModel struct {
+	inner  model.BaseChatModel
+	prompt string
+}
+
+func (cm *jsonPromptChatModel) withPrompt(input []*schema.Message) []*schema.Message {
+	return append([]*schema.Message{schema.SystemMessage(cm.prompt)}, input...)
+}
+
+func (cm *jsonPromptChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return cm.inner.Generate(ctx, cm.withPrompt(input), opts...)
+}
+
+func (cm *jsonPromptChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return cm.inner.Stream(ctx, cm.withPrompt(input), opts...)
+}
+
+func (cm *jsonPromptChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	tcm, ok := cm.inner.(model.ToolCallingChatModel)
+	if !ok {
+		return nil, errors.New("inner chat model does not support tool calling")
+	}
+	inner, err := tcm.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonPromptChatModel{inner: inner, prompt: cm.prompt}, nil
+}
+
+func (cm *jsonPromptChatModel) GetType() string {
+	if typ, ok := components.GetType(cm.inner); ok {
+		return typ
+	}
+	return "JSONPrompt"
+}
+
+func (cm *jsonPromptChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(cm.inner)
+}
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/libs/acl/openai"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+const testPersonSchema = `{"type":"object","properties":{"name":{"type":"string"},"age":{"type":"integer"}},"required":["name","age"]}`
+
+func personResponseFormat(t *testing.T) *openai.ChatCompletionResponseFormat {
+	var rf openai.ChatCompletionResponseFormat
+	raw := `{"type":"json_schema","json_schema":{"name":"person","strict":true,"schema":` + testPersonSchema + `}}`
+	if err := json.Unmarshal([]byte(raw), &rf); err != nil {
+		t.Fatalf("unmarshal response format failed: %v", err)
+	}
+	return &rf
+}
+
+func TestGetChatModel_AnthropicStructuredOutput(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Stream bool `json:"stream"`
+			Tools  []struct {
+				Name        string `json:"name"`
+				InputSchema struct {
+					Required []string `json:"required"`
+				} `json:"input_schema"`
+			} `json:"tools"`
+			ToolChoice struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+			} `json:"tool_choice"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Tools) != 1 || len(body.Tools[0].InputSchema.Required) != 2 ||
+			body.ToolChoice.Type != "tool" || body.ToolChoice.Name != body.Tools[0].Name {
+			t.Errorf("unexpected request: %+v", body)
+		}
+		name := body.Tools[0].Name
+		if body.Stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, ev := range []string{
+				`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"usage":{"input_tokens":10,"output_tokens":1}}}`,
+				`{"type":"content_block_start","index":0,"content_block":{"type":"tool_use","id":"toolu_1","name":"` + name + `","input":{}}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"{\"name\":\"Alice\","}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"\"age\":30}"}}`,
+				`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":7}}`,
+				`{"type":"message_stop"}`,
+			} {
+				_, _ = w.Write([]byte("data: " + ev + "\n\n"))
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"msg_1","type":"message","role":"assistant",
+			"content":[{"type":"tool_use","id":"toolu_1","name":"` + name + `","input":{"name":"Alice","age":30}}],
+			"stop_reason":"tool_use","usage":{"input_tokens":10,"output_tokens":5}}`))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	chatModel, err := NewModelKit(nil).GetChatModel(ctx, &domain.ModelMetadata{
+		Provider:       consts.ModelProviderAnthropic,
+		ModelName:      "claude-sonnet-4-5",
+		BaseURL:        ts.URL,
+		APIKey:         "sk-ant-test",
+		ResponseFormat: personResponseFormat(t),
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	msgs := []*schema.Message{schema.UserMessage("Alice is 30")}
+
+	resp, err := chatModel.Generate(ctx, msgs)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if resp.Content != `{"name":"Alice","age":30}` || len(resp.ToolCalls) != 0 || resp.ResponseMeta.FinishReason != "end_turn" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	sr, err := chatModel.Stream(ctx, msgs)
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	full := collectStream(t, sr)
+	if full.Content != `{"name":"Alice","age":30}` || len(full.ToolCalls) != 0 || full.ResponseMeta.FinishReason != "end_turn" {
+		t.Fatalf("unexpected stream result: %+v", full)
+	}
+}
+
+func TestGetChatModel_BedrockStructuredOutput(t *testing.T) {
+	modelID := "anthropic.claude-3-haiku-20240307-v1:0"
+	ts := standin.New(t, standin.Routes{"/model/" + modelID + "/converse": func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ToolConfig struct {
+				Tools []struct {
+					ToolSpec struct {
+						Name string `json:"name"`
+					} `json:"toolSpec"`
+				} `json:"tools"`
+				ToolChoice struct {
+					Tool struct {
+						Name string `json:"name"`
+					} `json:"tool"`
+				} `json:"toolChoice"`
+			} `json:"toolConfig"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		tc := body.ToolConfig
+		if len(tc.Tools) != 1 || tc.ToolChoice.Tool.Name != tc.Tools[0].ToolSpec.Name {
+			t.Errorf("unexpected tool config: %+v", tc)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"output": {"message": {"role": "assistant", "content": [{"toolUse": {"toolUseId": "t1", "name": "` + tc.Tools[0].ToolSpec.Name + `", "input": {"name": "Alice", "age": 30}}}]}},
+			"stopReason": "tool_use",
+			"usage": {"inputTokens": 10, "outputTokens": 5, "totalTokens": 15}
+		}`))
+	}}, standin.SigV4(testAWSCredentials, testAWSRegion, "bedrock"))
+
+	ctx := context.Background()
+	chatModel, err := NewModelKit(nil).GetChatModel(ctx, &domain.ModelMetadata{
+		Provider:           consts.ModelProviderAWSBedrock,
+		ModelName:          modelID,
+		BaseURL:            ts.URL,
+		AWSAccessKeyID:     testAWSAccessKeyID,
+		AWSSecretAccessKey: testAWSSecretAccessKey,
+		AWSRegion:          testAWSRegion,
+		ResponseFormat:     personResponseFormat(t),
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	resp, err := chatModel.Generate(ctx, []*schema.Message{schema.UserMessage("Alice is 30")})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if resp.Content != `{"name": "Alice", "age": 30}` || len(resp.ToolCalls) != 0 || resp.ResponseMeta.FinishReason != "end_turn" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestGetChatModel_StructuredOutputRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		provider consts.ModelProvider
+		model    string
+		path     string
+		chatAPI  consts.ChatAPI
+		resp     string
+		check    func(body map[string]any) bool
+	}{
+		{
+			name:     "deepseek",
+			provider: consts.ModelProviderDeepSeek,
+			model:    "deepseek-chat",
+			resp:     `{"id":"1","object":"chat.completion","model":"deepseek-chat","choices":[{"index":0,"message":{"role":"assistant","content":"{}"},"finish_reason":"stop"}]}`,
+			check: func(body map[string]any) bool {
+				rf, _ := body["response_format"].(map[string]any)
+				msgs, _ := body["messages"].([]any)
+				first, _ := msgs[0].(map[string]any)
+				content, _ := first["content"].(string)
+				return rf["type"] == "json_object" && len(msgs) == 2 && first["role"] == "system" &&
+					strings.Contains(content, "JSON") && strings.Contains(content, `"required":["name","age"]`)
+			},
+		},
+		{
+			name:     "ollama native",
+			provider: consts.ModelProviderOllama,
+			model:    "qwen3",
+			resp:     `{"model":"qwen3","message":{"role":"assistant","content":"{}"},"done":true}`,
+			check: func(body map[string]any) bool {
+				format, _ := body["format"].(map[string]any)
+				required, _ := format["required"].([]any)
+				return format["type"] == "object" && len(required) == 2
+			},
+		},
+		{
+			name:     "openai responses",
+			provider: consts.ModelProviderOpenAI,
+			model:    "gpt-5",
+			path:     "/v1",
+			chatAPI:  consts.ChatAPIResponses,
+			resp:     `{"id":"resp_1","status":"completed","output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":"{}"}]}]}`,
+			check: func(body map[string]any) bool {
+				text, _ := body["text"].(map[string]any)
+				format, _ := text["format"].(map[string]any)
+				schema, _ := format["schema"].(map[string]any)
+				return format["type"] == "json_schema" && format["name"] == "person" && format["strict"] == true && schema["type"] == "object"
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]any
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				if !tc.check(body) {
+					t.Errorf("unexpected request: %v", body)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tc.resp))
+			}))
+			defer ts.Close()
+
+			chatModel, err := NewModelKit(nil).GetChatModel(context.Background(), &domain.ModelMetadata{
+				Provider:       tc.provider,
+				ModelName:      tc.model,
+				BaseURL:        ts.URL + tc.path,
+				APIKey:         "sk-test",
+				ChatAPI:        tc.chatAPI,
+				ResponseFormat: personResponseFormat(t),
+			})
+			if err != nil {
+				t.Fatalf("GetChatModel failed: %v", err)
+			}
+			resp, err := chatModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("Alice is 30")})
+			if err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			if resp.Content != "{}" {
+				t.Fatalf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+// countingTransport 统计经过自定义 HTTPClient 的请求数
+type countingTransport struct {
+	n atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.n.Add(1)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// 分批、稀疏向量与 /info 缓存的细节见 components/embedder/tei 与 components/reranker/tei,
+// 这里校验 BaseURL、输出类型与 HTTPClient 的接入
+func TestGetEmbedder_TEI(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"GET /info":          standin.JSON(`{"model_id":"BAAI/bge-m3","max_client_batch_size":2,"model_type":{"embedding":{}}}`),
+		"POST /embed":        standin.JSON(`[[0.1,0.2]]`),
+		"POST /embed_sparse": standin.JSON(`[[{"index":7,"value":0.25}]]`),
+	})
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	transport := &countingTransport{}
+	outputType := "dense&sparse"
+	embedder, err := mk.GetEmbedder(ctx, &domain.ModelMetadata{
+		Provider:      consts.ModelProviderHuggingFaceTEI,
+		ModelName:     "BAAI/bge-m3",
+		BaseURL:       ts.URL + "/v1",
+		HTTPClient:    &http.Client{Transport: transport},
+		EmbedderParam: domain.EmbedderParam{OutputType: &outputType},
+	})
+	if err != nil {
+		t.Fatalf("GetEmbedder failed: %v", err)
+	}
+	resp, err := mk.UseEmbedder(ctx, embedder, []string{"a"})
+	if err != nil {
+		t.Fatalf("UseEmbedder failed: %v", err)
+	}
+	if item := resp.Embeddings[0]; len(item.Embedding) != 2 || len(item.SparseEmbedding) != 1 {
+		t.Fatalf("unexpected embedding item: %+v", item)
+	}
+	if got := transport.n.Load(); got != 3 {
+		t.Fatalf("expected all requests through the custom client, got %d", got)
+	}
+}
+
+func TestGetReranker_TEI(t *testing.T) {
+	var infoCalls atomic.Int32
+	ts := standin.New(t, standin.Routes{
+		"GET /info": func(w http.ResponseWriter, r *http.Request) {
+			// 第一次 /info 失败, 不应被缓存
+			if infoCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			standin.JSON(`{"model_id":"BAAI/bge-reranker-v2-m3","max_client_batch_size":2,"model_type":{"reranker":{}}}`)(w, r)
+		},
+		"POST /rerank": standin.JSON(`[{"index":1,"score":0.9},{"index":0,"score":0.1}]`),
+	})
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	transport := &countingTransport{}
+	rk, err := mk.GetReranker(ctx, &domain.ModelMetadata{
+		Provider:   consts.ModelProviderHuggingFaceTEI,
+		ModelName:  "BAAI/bge-reranker-v2-m3",
+		BaseURL:    ts.URL,
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("GetReranker failed: %v", err)
+	}
+	req := domain.RerankRequest{Query: "q", Documents: []string{"a", "bb"}}
+	for range 3 {
+		resp, err := rk.Rerank(ctx, req)
+		if err != nil {
+			t.Fatalf("Rerank failed: %v", err)
+		}
+		if len(resp.Results) != 2 || resp.Results[0].Index != 1 {
+			t.Fatalf("unexpected rerank result: %+v", resp)
+		}
+	}
+	if got := infoCalls.Load(); got != 2 {
+		t.Fatalf("expected /info to be retried once then cached, got %d calls", got)
+	}
+	if got := transport.n.Load(); got != 5 {
+		t.Fatalf("expected all requests through the custom client, got %d", got)
+	}
+}
+
+func TestModelList_TEI(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		"GET /info": standin.JSON(`{"model_id":"BAAI/bge-m3","max_input_length":8192,"max_client_batch_size":2,"model_type":{"embedding":{}}}`),
+	})
+
+	mk := NewModelKit(nil)
+	for typ, want := range map[string]int{"embedding": 1, "rerank": 0} {
+		resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+			Provider: string(consts.ModelProviderHuggingFaceTEI),
+			BaseURL:  ts.URL,
+			Type:     typ,
+		})
+		if err != nil || resp.Error != "" {
+			t.Fatalf("ModelList failed: %v %+v", err, resp)
+		}
+		if len(resp.Models) != want || (want == 1 && (resp.Models[0].Model != "BAAI/bge-m3" || resp.Models[0].ContextLength != 8192)) {
+			t.Fatalf("unexpected models for %s: %+v", typ, resp.Models)
+		}
+	}
+}
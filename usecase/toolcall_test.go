@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+var testTools = []*schema.ToolInfo{
+	{
+		Name: "get_weather",
+		Desc: "查询天气",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"city": {Type: schema.String, Required: true},
+		}),
+	},
+	{Name: "get_time", Desc: "查询当前时间"},
+}
+
+// 流式分块归并与补全的细节见 components/model/toolcall, 这里校验原生接口经过同样的包装
+func TestGetToolCallingChatModel_OllamaMissingIDs(t *testing.T) {
+	ts := standin.New(t, standin.Routes{
+		// 原生接口按行解析响应, 不能换行
+		"POST /api/chat": standin.JSON(`{"model":"qwen3","message":{"role":"assistant","content":"","tool_calls":[` +
+			`{"function":{"name":"get_weather","arguments":{"city":"Paris"}}},` +
+			`{"function":{"name":"get_time","arguments":{}}}]},"done":true}`),
+	})
+
+	ctx := context.Background()
+	chatModel, err := NewModelKit(nil).GetToolCallingChatModel(ctx, &domain.ModelMetadata{
+		Provider:  consts.ModelProviderOllama,
+		ModelName: "qwen3",
+		BaseURL:   ts.URL,
+	})
+	if err != nil {
+		t.Fatalf("GetToolCallingChatModel failed: %v", err)
+	}
+	withTools, err := chatModel.WithTools(testTools)
+	if err != nil {
+		t.Fatalf("WithTools failed: %v", err)
+	}
+	resp, err := withTools.Generate(ctx, []*schema.Message{schema.UserMessage("巴黎天气和时间")})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(resp.ToolCalls) != 2 {
+		t.Fatalf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+	for i, tc := range resp.ToolCalls {
+		if tc.Index == nil || *tc.Index != i || tc.ID == "" || tc.Type != "function" || !json.Valid([]byte(tc.Function.Arguments)) {
+			t.Fatalf("unexpected tool call %d: %+v", i, tc)
+		}
+	}
+	if resp.ToolCalls[0].ID == resp.ToolCalls[1].ID || resp.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Fatalf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+}
+
+func TestGetToolCallingChatModel_InvalidTools(t *testing.T) {
+	chatModel, err := NewModelKit(nil).GetToolCallingChatModel(context.Background(), &domain.ModelMetadata{
+		Provider:  consts.ModelProviderAnthropic,
+		ModelName: "claude-sonnet-4-5",
+		BaseURL:   "http://127.0.0.1:1",
+		APIKey:    "sk-ant-test",
+	})
+	if err != nil {
+		t.Fatalf("GetToolCallingChatModel failed: %v", err)
+	}
+	if _, err := chatModel.WithTools([]*schema.ToolInfo{testTools[0], testTools[0]}); err == nil {
+		t.Fatal("expected duplicate tool name error")
+	}
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"cloud.google.com/go/auth/credentials"
+	"cloud.google.com/go/auth/httptransport"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"google.golang.org/genai"
+
+	vertexEmb "github.com/chaitin/ModelKit/v2/components/embedder/vertex"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/pkg/extrabody"
+)
+
+const (
+	vertexDefaultLocation = "us-central1"
+	vertexScope           = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+type vertexAuth struct {
+	Project        string
+	Location       string
+	ServiceAccount string
+	APIKey         string
+	BaseURL        string
+}
+
+func vertexAuthFromMetadata(md *domain.ModelMetadata) vertexAuth {
+	return vertexAuth{
+		Project:        md.VertexProject,
+		Location:       md.VertexLocation,
+		ServiceAccount: md.VertexServiceAccount,
+		APIKey:         md.APIKey,
+		BaseURL:        md.BaseURL,
+	}
+}
+
+// customBaseURL 官方地址交给 SDK 按 location 选择区域端点, 其余地址(代理/私有网关)原样使用
+func (a vertexAuth) customBaseURL() string {
+	base := strings.TrimSuffix(a.BaseURL, "#")
+	u, err := url.Parse(base)
+	if base == "" || err != nil || strings.HasSuffix(u.Hostname(), "aiplatform.googleapis.com") {
+		return ""
+	}
+	return base
+}
+
+// clientConfig 优先使用服务账号(OAuth2 换取访问令牌), 其次使用 express 模式的 API Key
+func (a vertexAuth) clientConfig(httpClient *http.Client) (*genai.ClientConfig, error) {
+	cfg := &genai.ClientConfig{
+		Backend:     genai.BackendVertexAI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: a.customBaseURL()},
+	}
+	switch {
+	case a.ServiceAccount != "":
+		var sa struct {
+			Type      string `json:"type"`
+			ProjectID string `json:"project_id"`
+		}
+		if err := json.Unmarshal([]byte(a.ServiceAccount), &sa); err != nil {
+			return nil, fmt.Errorf("invalid vertex service account: %w", err)
+		}
+		// DetectDefault 同样接受 external_account 等类型, 这类凭证会按 JSON 中的地址读取文件或执行命令, 只允许服务账号
+		if sa.Type != "service_account" {
+			return nil, fmt.Errorf("invalid vertex service account: unsupported credentials type %q", sa.Type)
+		}
+		creds, err := credentials.DetectDefault(&credentials.DetectOptions{
+			CredentialsJSON: []byte(a.ServiceAccount),
+			Scopes:          []string{vertexScope},
+			Client:          httpClient,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid vertex service account: %w", err)
+		}
+		cfg.Credentials = creds
+		cfg.Project = a.Project
+		if cfg.Project == "" {
+			cfg.Project = sa.ProjectID
+		}
+		cfg.Location = a.Location
+		if cfg.Location == "" {
+			cfg.Location = vertexDefaultLocation
+		}
+		if cfg.Project == "" {
+			return nil, errors.New("missing vertex project")
+		}
+	case a.APIKey != "":
+		cfg.APIKey = a.APIKey
+	default:
+		return nil, errors.New("missing vertex credentials: set service account or api key")
+	}
+	return cfg, nil
+}
+
+func (a vertexAuth) newClient(ctx context.Context) (*genai.Client, error) {
+	cfg, err := a.clientConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	return genai.NewClient(ctx, cfg)
+}
+
+func newVertexChatModel(ctx context.Context, md *domain.ModelMetadata) (model.BaseChatModel, error) {
+	cfg, err := vertexAuthFromMetadata(md).clientConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	cfg.HTTPOptions.ExtraBody = geminiExtraBody(md)
+	// 自定义客户端时 genai 不再自动附加 OAuth2 鉴权
+	cfg.HTTPClient = extrabody.WrapClient(nil, md.ExtraBody)
+	if cfg.Credentials != nil {
+		if err := httptransport.AddAuthorizationMiddleware(cfg.HTTPClient, cfg.Credentials); err != nil {
+			return nil, err
+		}
+	}
+	client, err := genai.NewClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newGeminiChatModelWithClient(ctx, client, md)
+}
+
+func newVertexEmbedder(ctx context.Context, md *domain.ModelMetadata) (embedding.Embedder, error) {
+	client, err := vertexAuthFromMetadata(md).newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return vertexEmb.NewEmbedder(ctx, &vertexEmb.EmbeddingConfig{
+		Client:    client,
+		Model:     md.ModelName,
+		Dimension: md.EmbedderParam.Dimension,
+		TextType:  md.EmbedderParam.TextType,
+	})
+}
+
+func (m *ModelKit) listVertex(ctx context.Context, req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
+	auth := vertexAuth{
+		Project:        req.VertexProject,
+		Location:       req.VertexLocation,
+		ServiceAccount: req.VertexServiceAccount,
+		APIKey:         req.APIKey,
+		BaseURL:        req.BaseURL,
+	}
+	cfg, err := auth.clientConfig(httpClient)
+	if err != nil {
+		return &domain.ModelListResp{Error: err.Error()}, nil
+	}
+	if httpClient != nil && httpClient.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, httpClient.Timeout)
+		defer cancel()
+	}
+	client, err := genai.NewClient(ctx, cfg)
+	if err != nil {
+		return &domain.ModelListResp{Error: err.Error()}, nil
+	}
+
+	// Vertex AI 返回 publishers/google/models/<id> 形式的发布方模型
+	models := make([]domain.ModelListItem, 0)
+	for item, err := range client.Models.All(ctx) {
+		if err != nil {
+			return &domain.ModelListResp{Error: err.Error()}, nil
+		}
+		name := item.Name
+		if idx := strings.LastIndex(name, "/"); idx >= 0 {
+			name = name[idx+1:]
+		}
+		if name != "" {
+			models = append(models, domain.ModelListItem{Model: name})
+		}
+	}
+	if len(models) == 0 {
+		return &domain.ModelListResp{Error: "获取Vertex AI模型列表失败: 未找到可用模型"}, nil
+	}
+	filtered := FilterModelsByType(models, req)
+	return &domain.ModelListResp{Models: filtered}, nil
+}
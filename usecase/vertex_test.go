@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+	"github.com/chaitin/ModelKit/v2/internal/standin"
+)
+
+const testVertexAccessToken = "ya29.test-token"
+
+func TestGetChatModel_Vertex(t *testing.T) {
+	routes := standin.Routes{
+		"/v1beta1/projects/test-project/locations/us-central1/publishers/google/models/gemini-2.5-flash:generateContent": standin.JSON(`{
+			"candidates": [{"content": {"role": "model", "parts": [{"text": "Hello there!"}]}, "finishReason": "STOP"}],
+			"usageMetadata": {"promptTokenCount": 3, "candidatesTokenCount": 3, "totalTokenCount": 6}
+		}`),
+	}
+	ts := standin.New(t, routes, standin.GoogleAuth(t, routes, testVertexAccessToken))
+	sa := standin.GoogleServiceAccount(t, "test-project", ts.URL+"/token")
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	chatModel, err := mk.GetChatModel(ctx, &domain.ModelMetadata{
+		Provider:             consts.ModelProviderVertexAI,
+		ModelName:            "gemini-2.5-flash",
+		BaseURL:              ts.URL,
+		VertexServiceAccount: sa,
+	})
+	if err != nil {
+		t.Fatalf("GetChatModel failed: %v", err)
+	}
+	resp, err := chatModel.Generate(ctx, getInputMsg(&domain.CheckModelReq{}))
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if resp.Content != "Hello there!" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestGetEmbedder_Vertex(t *testing.T) {
+	// task_type、维度等参数的映射见 components/embedder/vertex, 这里校验项目与区域的接入
+	routes := standin.Routes{
+		"/v1beta1/projects/custom-project/locations/europe-west4/publishers/google/models/text-embedding-005:predict": standin.JSON(`{"predictions": [
+			{"embeddings": {"values": [0.1, 0.2], "statistics": {"token_count": 3, "truncated": false}}},
+			{"embeddings": {"values": [0.3, 0.4], "statistics": {"token_count": 5, "truncated": false}}}
+		]}`),
+	}
+	ts := standin.New(t, routes, standin.GoogleAuth(t, routes, testVertexAccessToken))
+	sa := standin.GoogleServiceAccount(t, "test-project", ts.URL+"/token")
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	dim := 256
+	textType := "query"
+	embedder, err := mk.GetEmbedder(ctx, &domain.ModelMetadata{
+		Provider:             consts.ModelProviderVertexAI,
+		ModelName:            "text-embedding-005",
+		BaseURL:              ts.URL,
+		VertexProject:        "custom-project",
+		VertexLocation:       "europe-west4",
+		VertexServiceAccount: sa,
+		EmbedderParam:        domain.EmbedderParam{Dimension: &dim, TextType: &textType},
+	})
+	if err != nil {
+		t.Fatalf("GetEmbedder failed: %v", err)
+	}
+	resp, err := mk.UseEmbedder(ctx, embedder, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("UseEmbedder failed: %v", err)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[1].TextIndex != 1 || resp.Embeddings[1].Embedding[1] < 0.39 || resp.Usage.TotalTokens != 8 {
+		t.Fatalf("unexpected embeddings: %+v", resp)
+	}
+}
+
+func TestModelList_Vertex(t *testing.T) {
+	routes := standin.Routes{
+		"/v1beta1/publishers/google/models": standin.JSON(`{"publisherModels": [
+			{"name": "publishers/google/models/gemini-2.5-flash"},
+			{"name": "publishers/google/models/text-embedding-005"}
+		]}`),
+	}
+	ts := standin.New(t, routes, standin.GoogleAuth(t, routes, testVertexAccessToken))
+	sa := standin.GoogleServiceAccount(t, "test-project", ts.URL+"/token")
+
+	mk := NewModelKit(nil)
+	resp, err := mk.ModelList(context.Background(), &domain.ModelListReq{
+		Provider:             string(consts.ModelProviderVertexAI),
+		BaseURL:              ts.URL,
+		Type:                 "embedding",
+		VertexServiceAccount: sa,
+	})
+	if err != nil {
+		t.Fatalf("ModelList failed: %v", err)
+	}
+	if resp.Error != "" {
+		t.Fatalf("ModelList returned error: %s", resp.Error)
+	}
+	if len(resp.Models) != 1 || resp.Models[0].Model != "text-embedding-005" {
+		t.Fatalf("unexpected models: %+v", resp.Models)
+	}
+}
+
+func TestGetChatModel_VertexRejectsNonServiceAccount(t *testing.T) {
+	for _, typ := range []string{"external_account", "authorized_user", "impersonated_service_account", ""} {
+		cred, _ := json.Marshal(map[string]any{
+			"type":              typ,
+			"project_id":        "test-project",
+			"credential_source": map[string]string{"file": "/etc/passwd"},
+		})
+		_, err := NewModelKit(nil).GetChatModel(context.Background(), &domain.ModelMetadata{
+			Provider:             consts.ModelProviderVertexAI,
+			ModelName:            "gemini-2.5-flash",
+			VertexServiceAccount: string(cred),
+		})
+		if err == nil || !strings.Contains(err.Error(), "unsupported credentials type") {
+			t.Errorf("type %q: expected unsupported credentials type error, got %v", typ, err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/volcengine/volcengine-go-sdk/service/ark"
+	"github.com/volcengine/volcengine-go-sdk/volcengine"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/credentials"
+	"github.com/volcengine/volcengine-go-sdk/volcengine/session"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+const (
+	volcengineDefaultRegion = "cn-beijing"
+	volcenginePageSize      = 100
+)
+
+// listVolcengine 设置 AccessKey 时通过管控面 ListEndpoints 列出推理接入点(ep-xxx)及其基础模型,
+// 并合并方舟 /models 返回的模型. 列举失败只记录日志, 两者都没有结果时退回内置模型列表
+func (m *ModelKit) listVolcengine(ctx context.Context, req *domain.ModelListReq, httpClient *http.Client) (*domain.ModelListResp, error) {
+	var models []domain.ModelListItem
+	if req.VolcengineAccessKey != "" || req.VolcengineSecretKey != "" {
+		endpoints, err := listVolcengineEndpoints(ctx, req, httpClient)
+		if err != nil && m.logger != nil {
+			m.logger.Warn("Volcengine list endpoints failed", "error", err)
+		}
+		models = endpoints
+	}
+
+	apiModels, err := reqModelListApi(req, httpClient, &domain.OpenAIResp{})
+	if err != nil && m.logger != nil {
+		m.logger.Warn("Volcengine list models failed", "error", err)
+	}
+	models = append(models, apiModels...)
+	if len(models) == 0 {
+		if m.logger != nil {
+			m.logger.Warn("Volcengine list models is empty, fallback to static models")
+		}
+		return m.listStaticProvider(req, consts.ModelProviderVolcengine)
+	}
+	filtered := FilterModelsByType(models, req)
+	return &domain.ModelListResp{Models: filtered}, nil
+}
+
+// listVolcengineEndpoints 分页调用 ListEndpoints, 只返回运行中的接入点, BaseModel 为基础模型名称与版本
+func listVolcengineEndpoints(ctx context.Context, req *domain.ModelListReq, httpClient *http.Client) ([]domain.ModelListItem, error) {
+	endpoint, region := volcengineOpenAPI(req.BaseURL)
+	cfg := volcengine.NewConfig().
+		WithRegion(region).
+		WithCredentials(credentials.NewStaticCredentials(req.VolcengineAccessKey, req.VolcengineSecretKey, "")).
+		WithHTTPClient(httpClient)
+	if endpoint != "" {
+		cfg = cfg.WithEndpoint(endpoint)
+	}
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := ark.New(sess)
+
+	var models []domain.ModelListItem
+	for page := int32(1); ; page++ {
+		out, err := client.ListEndpointsWithContext(ctx, &ark.ListEndpointsInput{
+			PageNumber: volcengine.Int32(page),
+			PageSize:   volcengine.Int32(volcenginePageSize),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range out.Items {
+			if item == nil || volcengine.StringValue(item.Status) != ark.EnumOfStatusForListEndpointsOutputRunning {
+				continue
+			}
+			models = append(models, domain.ModelListItem{
+				Model:     volcengine.StringValue(item.Id),
+				BaseModel: volcengineFoundationModel(item.ModelReference),
+			})
+		}
+		if len(out.Items) < volcenginePageSize || page*volcenginePageSize >= volcengine.Int32Value(out.TotalCount) {
+			return models, nil
+		}
+	}
+}
+
+func volcengineFoundationModel(ref *ark.ModelReferenceForListEndpointsOutput) string {
+	if ref == nil || ref.FoundationModel == nil {
+		return ""
+	}
+	name := volcengine.StringValue(ref.FoundationModel.Name)
+	if version := volcengine.StringValue(ref.FoundationModel.ModelVersion); name != "" && version != "" {
+		return name + "-" + version
+	}
+	return name
+}
+
+// volcengineOpenAPI 根据方舟推理地址确定管控面地址与区域:
+// ark.<region>.volces.com 对应 ark.<region>.volcengineapi.com, 其他地址(代理/私有网关)使用同一服务地址
+func volcengineOpenAPI(baseURL string) (string, string) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "#"))
+	if baseURL == "" || err != nil || u.Host == "" {
+		return "", volcengineDefaultRegion
+	}
+	if rest, ok := strings.CutPrefix(u.Hostname(), "ark."); ok {
+		if region, ok := strings.CutSuffix(rest, ".volces.com"); ok {
+			return "https://ark." + region + ".volcengineapi.com", region
+		}
+	}
+	return u.Scheme + "://" + u.Host, volcengineDefaultRegion
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chaitin/ModelKit/v2/consts"
+	"github.com/chaitin/ModelKit/v2/domain"
+)
+
+func TestGetEmbedder_Voyage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" || r.Header.Get("Authorization") != "Bearer pa-test" {
+			t.Errorf("unexpected request: %s %v", r.URL.Path, r.Header)
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["input_type"] != "document" || body["output_dimension"] != float64(512) || body["output_dtype"] != "int8" {
+			t.Errorf("unexpected request: %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"object":"embedding","embedding":[12,-7],"index":0}],"model":"voyage-3.5","usage":{"total_tokens":4}}`))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	dim := 512
+	textType := "document"
+	dtype := "int8"
+	embedder, err := mk.GetEmbedder(ctx, &domain.ModelMetadata{
+		Provider:      consts.ModelProviderVoyageAI,
+		ModelName:     "voyage-3.5",
+		BaseURL:       ts.URL + "/v1",
+		APIKey:        "pa-test",
+		EmbedderParam: domain.EmbedderParam{Dimension: &dim, TextType: &textType, OutputDtype: &dtype},
+	})
+	if err != nil {
+		t.Fatalf("GetEmbedder failed: %v", err)
+	}
+	resp, err := mk.UseEmbedder(ctx, embedder, []string{"a"})
+	if err != nil {
+		t.Fatalf("UseEmbedder failed: %v", err)
+	}
+	if len(resp.Embeddings) != 1 || resp.Embeddings[0].Embedding[1] != -7 || resp.Usage.TotalTokens != 4 {
+		t.Fatalf("unexpected embeddings: %+v", resp)
+	}
+}
+
+func TestGetReranker_Voyage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/rerank" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["top_k"] != float64(1) || body["truncation"] != false {
+			t.Errorf("unexpected request: %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"relevance_score":0.87,"index":1,"document":"火鸡"}],"model":"rerank-2.5","usage":{"total_tokens":9}}`))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	mk := NewModelKit(nil)
+	transport := &countingTransport{}
+	rk, err := mk.GetReranker(ctx, &domain.ModelMetadata{
+		Provider:   consts.ModelProviderVoyageAI,
+		ModelName:  "rerank-2.5",
+		BaseURL:    ts.URL + "/v1",
+		APIKey:     "pa-test",
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("GetReranker failed: %v", err)
+	}
+	n := 1
+	truncation := false
+	resp, err := rk.Rerank(ctx, domain.RerankRequest{
+		Query:           "动物",
+		Documents:       []string{"火", "火鸡"},
+		ReturnDocuments: true,
+		N:               &n,
+		Truncation:      &truncation,
+	})
+	if err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Index != 1 || resp.Results[0].Document != "火鸡" || resp.Usage == nil || resp.Usage.TotalTokens != 9 {
+		t.Fatalf("unexpected rerank result: %+v", resp)
+	}
+	if got := transport.n.Load(); got != 1 {
+		t.Fatalf("expected the request through the custom client, got %d", got)
+	}
+}
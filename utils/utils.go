@@ -18,6 +18,7 @@ import (
 	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
 	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
 	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/chaitin/ModelKit/v2/consts"
 	"github.com/chaitin/ModelKit/v2/domain"
 	"github.com/chaitin/ModelKit/v2/pkg/request"
 	"github.com/yuin/goldmark"
@@ -298,5 +299,9 @@ func GetHttpClientWithAPIHeaderMap(header string) *http.Client {
 
 func GetQuery(req *domain.ModelListReq) (request.Query, error) {
 	q := make(request.Query, 0)
+	if consts.ParseModelProvider(req.Provider) == consts.ModelProviderAnthropic {
+		// Anthropic 默认分页大小为20
+		q["limit"] = "1000"
+	}
 	return q, nil
 }